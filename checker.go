@@ -4,6 +4,9 @@
 package schema
 
 import (
+	"fmt"
+	"math/rand"
+	"reflect"
 	"strings"
 )
 
@@ -17,6 +20,12 @@ type Checker interface {
 	Coerce(v interface{}, path []string) (newv interface{}, err error)
 }
 
+// wrapper is implemented by checkers that add behaviour to another
+// checker, so that the underlying checker can be found.
+type wrapper interface {
+	unwrap() Checker
+}
+
 // Any returns a Checker that succeeds with any input value and
 // results in the value itself unprocessed.
 func Any() Checker {
@@ -34,11 +43,14 @@ func (c anyC) Coerce(v interface{}, path []string) (interface{}, error) {
 // that succeeds will be returned by the OneOf checker itself.  If no
 // checker succeeds, OneOf will return an error on coercion.
 func OneOf(options ...Checker) Checker {
-	return oneOfC{options}
+	return oneOfC{options, anySecret(options...)}
 }
 
 type oneOfC struct {
 	options []Checker
+
+	// secret holds whether any of the options is secret.
+	secret bool
 }
 
 func (c oneOfC) Coerce(v interface{}, path []string) (interface{}, error) {
@@ -48,7 +60,34 @@ func (c oneOfC) Coerce(v interface{}, path []string) (interface{}, error) {
 			return newv, nil
 		}
 	}
-	return nil, error_{"", v, path}
+	return nil, error_{"", errorValue(c, v), path}
+}
+
+// Uncoerce implements Encoder. The value is converted with the first
+// option that converts it into a value the option accepts.
+func (c oneOfC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	for _, o := range c.options {
+		u, err := uncoerce(o, v, path)
+		if err != nil {
+			continue
+		}
+		if _, err := o.Coerce(u, path); err == nil {
+			return u, nil
+		}
+	}
+	return nil, error_{"", errorValue(c, v), path}
+}
+
+func (c oneOfC) holdsSecret() bool {
+	return c.secret
+}
+
+// redact masks the secret values in v held by any of the options.
+func (c oneOfC) redact(v interface{}) interface{} {
+	for _, o := range c.options {
+		v = redact(o, v)
+	}
+	return v
 }
 
 // pathAsPrefix returns a string consisting of the path elements
@@ -69,3 +108,67 @@ func pathAsPrefix(path []string) string {
 	}
 	return s + ": "
 }
+
+// assignableValue returns v as a value to be stored in a container
+// element of type t, failing if v is not assignable to t.
+func assignableValue(t reflect.Type, v interface{}, path []string) (reflect.Value, error) {
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, error_{t.String(), v, path}
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(t) {
+		return reflect.Value{}, error_{t.String(), v, path}
+	}
+	return rv, nil
+}
+
+// Describe implements Descriptor.
+func (c anyC) Describe() Description {
+	return Description{Kind: KindAny, Label: "any value"}
+}
+
+// Describe implements Descriptor.
+func (c oneOfC) Describe() Description {
+	options := describeAll(c.options)
+	return Description{
+		Kind:    KindOneOf,
+		Label:   "one of " + joinLabels(options, ", "),
+		Options: options,
+	}
+}
+
+func (c anyC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "", nil
+}
+
+func (c oneOfC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	if len(c.options) == 0 {
+		return nil, fmt.Errorf("%scannot generate an example for OneOf with no options", pathAsPrefix(path))
+	}
+	return example(c.options[0], opts, path)
+}
+
+func (c anyC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	switch r.Intn(3) {
+	case 0:
+		return r.Intn(2001) - 1000, nil
+	case 1:
+		return randomString(r, 0, 10), nil
+	}
+	return r.Intn(2) == 1, nil
+}
+
+func (c oneOfC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	if len(c.options) == 0 {
+		return nil, fmt.Errorf("%scannot generate a value for OneOf with no options", pathAsPrefix(path))
+	}
+	return generate(c.options[r.Intn(len(c.options))], r, path)
+}
+
+func (c oneOfC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	return invalidLeaf(c, r, path)
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Incompatibility describes a change between two versions of a schema
+// that may cause values accepted by the old version to be rejected, or
+// to be coerced differently, by the new one.
+type Incompatibility struct {
+	// Path holds the path to the changed value, formatted as in the
+	// documentation generated by Markdown, such as "units[].name".
+	Path string
+
+	// Message describes the change.
+	Message string
+}
+
+// String returns the message prefixed by the path.
+func (i Incompatibility) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// Compatible compares old and new, two versions of a schema, and
+// returns the changes that break compatibility, such as fields removed
+// from a strict FieldMap, new required fields, narrowed types, changed
+// defaults or removed FieldMapSet branches. It returns nil when every
+// value accepted by old is accepted by new and coerced to the same
+// value, as far as can be told from what Describe reports about both.
+//
+// Custom checkers that don't implement Descriptor are only compared by
+// type.
+func Compatible(old, new Checker) []Incompatibility {
+	return compatible(Describe(old), Describe(new), "")
+}
+
+// widens maps the kinds of checkers to other kinds that accept all the
+// values they accept, and coerce them to the same values. Kinds that
+// accept more values but coerce them differently, such as ForceInt
+// producing int values where Int produces int64 ones, are not listed.
+var widens = map[Kind][]Kind{
+	KindUint:           {KindForceUint},
+	KindString:         {KindStringified},
+	KindNonEmptyString: {KindString, KindStringified},
+	KindUUID:           {KindString, KindStringified},
+}
+
+func compatible(old, new Description, path string) []Incompatibility {
+	var changes []Incompatibility
+	report := func(path, format string, args ...interface{}) {
+		changes = append(changes, Incompatibility{path, fmt.Sprintf(format, args...)})
+	}
+	if old.Nullable && !new.Nullable && !accepts(new, nil) {
+		report(path, "null is no longer accepted")
+	}
+	switch {
+	case new.Kind == KindAny:
+		return changes
+	case old.Kind == KindConst:
+		if !accepts(new, old.Value) {
+			report(path, "%s is no longer accepted", old.Label)
+		}
+		return changes
+	case old.Kind == KindNil:
+		if !accepts(new, nil) {
+			report(path, "null is no longer accepted")
+		}
+		return changes
+	case old.Kind == KindOneOf:
+		for _, option := range old.Options {
+			changes = append(changes, compatible(option, new, path)...)
+		}
+		return changes
+	case new.Kind == KindOneOf:
+		for _, option := range new.Options {
+			if len(compatible(old, option, path)) == 0 {
+				return changes
+			}
+		}
+		report(path, "type changed from %s to %s", old.Label, new.Label)
+		return changes
+	case old.Kind != new.Kind:
+		for _, kind := range widens[old.Kind] {
+			if kind == new.Kind {
+				return changes
+			}
+		}
+		report(path, "type changed from %s to %s", old.Label, new.Label)
+		return changes
+	case old.Kind == KindCustom:
+		if old.Label != new.Label {
+			report(path, "type changed from %s to %s", old.Label, new.Label)
+		}
+		return changes
+	}
+
+	switch old.Kind {
+	case KindList, KindSet:
+		changes = append(changes, compatible(*old.Elem, *new.Elem, path+"[]")...)
+	case KindTuple:
+		for i, item := range old.Items {
+			if i < len(new.Items) {
+				changes = append(changes, compatible(item, new.Items[i], fmt.Sprintf("%s[%d]", path, i))...)
+			} else if new.Elem != nil {
+				changes = append(changes, compatible(item, *new.Elem, fmt.Sprintf("%s[%d]", path, i))...)
+			} else {
+				report(path, "now accepts at most %s", elements(len(new.Items)))
+				break
+			}
+		}
+		if old.Elem != nil {
+			if new.Elem == nil {
+				report(path, "additional elements are no longer accepted")
+			} else {
+				changes = append(changes, compatible(*old.Elem, *new.Elem, path+"[]")...)
+			}
+		}
+	case KindMap:
+		changes = append(changes, compatible(*old.Key, *new.Key, joinDocPath(path, "{}"))...)
+		changes = append(changes, compatible(*old.Elem, *new.Elem, joinDocPath(path, "*"))...)
+	case KindStringMap:
+		changes = append(changes, compatible(*old.Elem, *new.Elem, joinDocPath(path, "*"))...)
+	case KindJSON:
+		changes = append(changes, compatible(*old.Elem, *new.Elem, path)...)
+	case KindFieldMap:
+		changes = append(changes, compatibleFields(old, new, path)...)
+	case KindFieldMapSet, KindFieldMapSwitch:
+		changes = append(changes, compatibleBranches(old, new, path)...)
+	}
+	// Bounds are compared numerically, so that loosening them is not
+	// reported.
+	if new.MinLen > old.MinLen {
+		report(path, "new constraint: at least %s", elements(new.MinLen))
+	}
+	if new.MaxLen > 0 && (old.MaxLen == 0 || new.MaxLen < old.MaxLen) {
+		report(path, "new constraint: at most %s", elements(new.MaxLen))
+	}
+	if new.MaxKeys > 0 && (old.MaxKeys == 0 || new.MaxKeys < old.MaxKeys) {
+		report(path, "new constraint: at most %s", keysLabel(new.MaxKeys))
+	}
+	bounds := boundConstraints(new)
+	for _, constraint := range new.Constraints {
+		if !containsString(old.Constraints, constraint) && !containsString(bounds, constraint) {
+			report(path, "new constraint: %s", constraint)
+		}
+	}
+	return changes
+}
+
+// compatibleFields returns the incompatible changes between the fields
+// of two FieldMaps.
+func compatibleFields(old, new Description, path string) []Incompatibility {
+	var changes []Incompatibility
+	report := func(path, format string, args ...interface{}) {
+		changes = append(changes, Incompatibility{path, fmt.Sprintf(format, args...)})
+	}
+	strict := containsString(new.Constraints, "no unknown keys")
+	newFields := make(map[string]FieldDescription, len(new.Fields))
+	for _, f := range new.Fields {
+		newFields[f.Name] = f
+	}
+	for _, of := range old.Fields {
+		fpath := joinDocPath(path, of.Name)
+		nf, ok := newFields[of.Name]
+		if !ok {
+			// Unknown keys are ignored unless the new FieldMap is
+			// strict.
+			if strict {
+				report(fpath, "field was removed")
+			}
+			continue
+		}
+		changes = append(changes, compatible(of.Checker, nf.Checker, fpath)...)
+		switch {
+		case nf.Required && !of.Required:
+			report(fpath, "field is now required")
+		case of.HasDefault && nf.HasDefault && !reflect.DeepEqual(coercedDefault(of), coercedDefault(nf)):
+			report(fpath, "default changed from %s to %s", docValue(of.Default), docValue(nf.Default))
+		case of.HasDefault && !nf.HasDefault && !nf.Required:
+			report(fpath, "default %s was removed", docValue(of.Default))
+		}
+		for _, alias := range of.Aliases {
+			if !containsString(nf.Aliases, alias) {
+				report(fpath, "alias %q was removed", alias)
+			}
+		}
+	}
+	for _, nf := range new.Fields {
+		if nf.Required && !hasField(old, nf.Name) {
+			report(joinDocPath(path, nf.Name), "new required field")
+		}
+	}
+	if old.Elem != nil {
+		if new.Elem != nil {
+			changes = append(changes, compatible(*old.Elem, *new.Elem, joinDocPath(path, "*"))...)
+		} else if strict {
+			report(path, "additional keys are no longer accepted")
+		}
+	}
+	return changes
+}
+
+// compatibleBranches returns the incompatible changes between the
+// branches of two FieldMapSet or FieldMapSwitch checkers. Branches are
+// matched by selector value, or by position when the values are not
+// known.
+func compatibleBranches(old, new Description, path string) []Incompatibility {
+	var changes []Incompatibility
+	if old.Selector != new.Selector {
+		return []Incompatibility{{path, fmt.Sprintf("selector changed from %q to %q", old.Selector, new.Selector)}}
+	}
+	for i, ob := range old.Branches {
+		nb, ok := matchBranch(ob, i, new.Branches)
+		if !ok {
+			msg := fmt.Sprintf("branch %d was removed", i)
+			if ob.HasValue {
+				msg = fmt.Sprintf("branch for %s %s was removed", old.Selector, docValue(ob.Value))
+			}
+			changes = append(changes, Incompatibility{path, msg})
+			continue
+		}
+		changes = append(changes, compatible(ob.Checker, nb.Checker, path)...)
+	}
+	return changes
+}
+
+// matchBranch returns the branch in branches matching ob, found at
+// index i in the old branches.
+func matchBranch(ob Branch, i int, branches []Branch) (Branch, bool) {
+	if !ob.HasValue {
+		if i < len(branches) && !branches[i].HasValue {
+			return branches[i], true
+		}
+		return Branch{}, false
+	}
+	var fallback *Branch
+	for j, nb := range branches {
+		if nb.HasValue && reflect.DeepEqual(nb.Value, ob.Value) {
+			return nb, true
+		}
+		if !nb.HasValue && fallback == nil {
+			fallback = &branches[j]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Branch{}, false
+}
+
+// accepts reports whether the checker described by d accepts v.
+func accepts(d Description, v interface{}) bool {
+	if d.checker == nil {
+		return false
+	}
+	_, err := d.checker.Coerce(v, nil)
+	return err == nil
+}
+
+// coercedDefault returns the default of f as coerced by the field
+// checker, so that defaults such as 1 and int64(1) compare equal. The
+// default is returned as is if it can't be coerced.
+func coercedDefault(f FieldDescription) interface{} {
+	if f.Checker.checker == nil {
+		return f.Default
+	}
+	v, err := f.Checker.checker.Coerce(f.Default, nil)
+	if err != nil {
+		return f.Default
+	}
+	return v
+}
+
+func hasField(d Description, name string) bool {
+	for _, f := range d.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func changes(old, new schema.Checker) []string {
+	var out []string
+	for _, change := range schema.Compatible(old, new) {
+		out = append(out, change.String())
+	}
+	return out
+}
+
+func (s *S) TestCompatibleSame(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"name": schema.String(),
+		"port": schema.Int(),
+	}, schema.Defaults{"port": 80})
+	c.Assert(schema.Compatible(sch, sch), gc.HasLen, 0)
+}
+
+func (s *S) TestCompatibleTypes(c *gc.C) {
+	for _, test := range []struct {
+		old, new schema.Checker
+		changes  []string
+	}{
+		{schema.Uint(), schema.ForceUint(), nil},
+		{schema.NonEmptyString(""), schema.String(), nil},
+		{schema.UUID(), schema.String(), nil},
+		{schema.Int(), schema.ForceInt(), []string{"type changed from int to number as int"}},
+		{schema.URL(), schema.String(), []string{"type changed from url to string"}},
+		{schema.Bool(), schema.Stringified(), []string{"type changed from bool to string"}},
+		{schema.Int(), schema.Any(), nil},
+		{schema.Int(), schema.OneOf(schema.String(), schema.Int()), nil},
+		{schema.Const("a"), schema.String(), nil},
+		{schema.Const("a"), schema.OneOf(schema.Const("a"), schema.Const("b")), nil},
+		{schema.Nullable(schema.Int()), schema.Nullable(schema.Int()), nil},
+		{schema.String(), schema.UUID(), []string{"type changed from string to uuid"}},
+		{schema.Const("a"), schema.Const("b"), []string{`"a" is no longer accepted`}},
+		{schema.OneOf(schema.Const("a"), schema.Const("b")), schema.Const("a"), []string{`"b" is no longer accepted`}},
+		{schema.Nullable(schema.Int()), schema.Int(), []string{"null is no longer accepted"}},
+		{schema.List(schema.Int()), schema.List(schema.String()), []string{"[]: type changed from int to string"}},
+		{schema.List(schema.Int()), schema.ListWith(schema.Int(), schema.ListOptions{MaxLen: 2}), []string{"new constraint: at most 2 elements"}},
+		{schema.StringMap(schema.Int()), schema.StringMap(schema.Bool()), []string{"*: type changed from int to bool"}},
+		{schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 2, MaxLen: 3}), schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 1, MaxLen: 5}), nil},
+		{schema.ListWith(schema.Int(), schema.ListOptions{MaxLen: 3}), schema.List(schema.Int()), nil},
+		{schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 1, MaxLen: 5}), schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 2, MaxLen: 3}), []string{"new constraint: at least 2 elements", "new constraint: at most 3 elements"}},
+		{schema.StringMapWith(schema.Int(), schema.StringMapOptions{MaxKeys: 2}), schema.StringMapWith(schema.Int(), schema.StringMapOptions{MaxKeys: 10}), nil},
+		{schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{MaxKeys: 2}), schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{MaxKeys: 1}), []string{"new constraint: at most 1 key"}},
+		{schema.TupleWith([]schema.Checker{schema.Int(), schema.Int()}, schema.TupleOptions{Optional: 1}), schema.Tuple(schema.Int(), schema.Int()), []string{"new constraint: at least 2 elements"}},
+		{schema.Tuple(schema.Int()), schema.TupleWith([]schema.Checker{schema.Int(), schema.Int()}, schema.TupleOptions{Optional: 1}), nil},
+		{schema.Tuple(schema.Int()), schema.Tuple(schema.Int(), schema.Int()), []string{"new constraint: at least 2 elements"}},
+		{schema.Tuple(schema.Int(), schema.Int()), schema.Tuple(schema.Int()), []string{"now accepts at most 1 element"}},
+	} {
+		c.Check(changes(test.old, test.new), gc.DeepEquals, test.changes, gc.Commentf("%s -> %s", schema.Describe(test.old).Label, schema.Describe(test.new).Label))
+	}
+}
+
+func (s *S) TestCompatibleFieldMap(c *gc.C) {
+	old := schema.FieldMapWith(schema.Fields{
+		"name":    schema.String(),
+		"port":    schema.Int(),
+		"mode":    schema.String(),
+		"legacy":  schema.Int(),
+		"timeout": schema.TimeDuration(),
+		"nested": schema.FieldMap(schema.Fields{
+			"id": schema.Int(),
+		}, nil),
+	}, schema.Defaults{
+		"port":    80,
+		"mode":    "fast",
+		"legacy":  schema.Omit,
+		"timeout": "1s",
+	}, schema.FieldMapOptions{
+		Aliases: map[string]string{"n": "name"},
+	})
+	new := schema.FieldMapWith(schema.Fields{
+		"name":    schema.String(),
+		"port":    schema.Int(),
+		"mode":    schema.String(),
+		"timeout": schema.TimeDuration(),
+		"owner":   schema.String(),
+		"extra":   schema.Optional(schema.String()),
+		"nested": schema.FieldMap(schema.Fields{
+			"id": schema.String(),
+		}, nil),
+	}, schema.Defaults{
+		"port": 8080,
+		"mode": schema.Omit,
+	}, schema.FieldMapOptions{
+		Strict: true,
+	})
+	c.Assert(changes(old, new), gc.DeepEquals, []string{
+		`legacy: field was removed`,
+		`mode: default "fast" was removed`,
+		`name: alias "n" was removed`,
+		`nested.id: type changed from int to string`,
+		`port: default changed from 80 to 8080`,
+		`timeout: field is now required`,
+		`owner: new required field`,
+		`new constraint: no unknown keys`,
+	})
+}
+
+func (s *S) TestCompatibleCoercedDefaults(c *gc.C) {
+	old := schema.FieldMap(schema.Fields{
+		"port":    schema.Int(),
+		"timeout": schema.TimeDuration(),
+		"size":    schema.Size(),
+	}, schema.Defaults{
+		"port":    80,
+		"timeout": "1m",
+		"size":    "1G",
+	})
+	new := schema.FieldMap(schema.Fields{
+		"port":    schema.Int(),
+		"timeout": schema.TimeDuration(),
+		"size":    schema.Size(),
+	}, schema.Defaults{
+		"port":    int64(80),
+		"timeout": time.Minute,
+		"size":    "2G",
+	})
+	c.Assert(changes(old, new), gc.DeepEquals, []string{
+		`size: default changed from "1G" to "2G"`,
+	})
+}
+
+func (s *S) TestCompatibleBranches(c *gc.C) {
+	old := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"file": schema.FieldMap(schema.Fields{"type": schema.Const("file"), "path": schema.String()}, nil),
+		"url":  schema.FieldMap(schema.Fields{"type": schema.Const("url"), "url": schema.String()}, nil),
+	}, nil)
+	new := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"file": schema.FieldMap(schema.Fields{"type": schema.Const("file"), "path": schema.Int()}, nil),
+	}, nil)
+	c.Assert(changes(old, new), gc.DeepEquals, []string{
+		`path: type changed from string to int`,
+		`branch for type "url" was removed`,
+	})
+
+	oldSet := schema.FieldMapSet("type", []schema.Checker{
+		schema.FieldMap(schema.Fields{"type": schema.Const("a")}, nil),
+		schema.FieldMap(schema.Fields{"type": schema.Const("b")}, nil),
+	})
+	newSet := schema.FieldMapSet("type", []schema.Checker{
+		schema.FieldMap(schema.Fields{"type": schema.Const("b")}, nil),
+	})
+	c.Assert(changes(oldSet, newSet), gc.DeepEquals, []string{`branch for type "a" was removed`})
+}
@@ -5,6 +5,7 @@ package schema
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
 )
 
@@ -55,3 +56,29 @@ func (c nilC) Coerce(v interface{}, path []string) (interface{}, error) {
 	label := fmt.Sprintf("empty %s", c.valueLabel)
 	return nil, error_{label, v, path}
 }
+
+// Describe implements Descriptor.
+func (c constC) Describe() Description {
+	return Description{Kind: KindConst, Label: fmt.Sprintf("%#v", c.value), Value: c.value}
+}
+
+// Describe implements Descriptor.
+func (c nilC) Describe() Description {
+	return Description{Kind: KindNil, Label: "empty " + c.valueLabel}
+}
+
+func (c constC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return c.value, nil
+}
+
+func (c nilC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return nil, nil
+}
+
+func (c constC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return c.value, nil
+}
+
+func (c nilC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Constraint checks a relationship between the fields of a map coerced
+// by a FieldMap checker. The Check method is called with the coerced map
+// and the path of the map itself, and returns an error describing the
+// violation, if any.
+type Constraint interface {
+	Check(m map[string]interface{}, path []string) error
+}
+
+// RequiredTogether returns a Constraint that succeeds if either all or
+// none of the named fields are present in the coerced map.
+func RequiredTogether(fields ...string) Constraint {
+	return requiredTogetherC{fields}
+}
+
+type requiredTogetherC struct {
+	fields []string
+}
+
+func (c requiredTogetherC) Check(m map[string]interface{}, path []string) error {
+	if n := len(presentFields(m, c.fields)); n != 0 && n != len(c.fields) {
+		return fieldsError{path, c.fields, "must be specified together"}
+	}
+	return nil
+}
+
+// MutuallyExclusive returns a Constraint that succeeds if at most one
+// of the named fields is present in the coerced map.
+func MutuallyExclusive(fields ...string) Constraint {
+	return mutuallyExclusiveC{fields}
+}
+
+type mutuallyExclusiveC struct {
+	fields []string
+}
+
+func (c mutuallyExclusiveC) Check(m map[string]interface{}, path []string) error {
+	if present := presentFields(m, c.fields); len(present) > 1 {
+		return fieldsError{path, present, "only one may be specified"}
+	}
+	return nil
+}
+
+// ExactlyOneOf returns a Constraint that succeeds if exactly one of the
+// named fields is present in the coerced map.
+func ExactlyOneOf(fields ...string) Constraint {
+	return exactlyOneOfC{fields}
+}
+
+type exactlyOneOfC struct {
+	fields []string
+}
+
+func (c exactlyOneOfC) Check(m map[string]interface{}, path []string) error {
+	switch present := presentFields(m, c.fields); len(present) {
+	case 1:
+		return nil
+	case 0:
+		return fieldsError{path, c.fields, "exactly one must be specified"}
+	default:
+		return fieldsError{path, present, "exactly one must be specified"}
+	}
+}
+
+// RequiredIf returns a Constraint that requires all the fields in
+// required to be present in the coerced map when the coerced value of
+// field equals value. Values are compared with reflect.DeepEqual after
+// coercion, so value should have the type produced by the field checker.
+//
+// Example:
+// schema.RequiredIf("tls", true, "cert", "key") will report
+// `cert: required when tls is true` if tls is true and cert is missing.
+func RequiredIf(field string, value interface{}, required ...string) Constraint {
+	return requiredIfC{field, value, required}
+}
+
+type requiredIfC struct {
+	field    string
+	value    interface{}
+	required []string
+}
+
+func (c requiredIfC) Check(m map[string]interface{}, path []string) error {
+	if v, ok := m[c.field]; !ok || !reflect.DeepEqual(v, c.value) {
+		return nil
+	}
+	var missing []string
+	for _, f := range c.required {
+		if !isPresent(m, f) {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) > 0 {
+		return fieldsError{path, missing, fmt.Sprintf("required when %s is %#v", c.field, c.value)}
+	}
+	return nil
+}
+
+// Predicate returns a Constraint that calls check with the coerced map
+// and fails if it returns false. The message describes the rule being
+// enforced, and fields names the fields involved so that they can be
+// referenced by the resulting error.
+//
+// Example:
+// schema.Predicate("min-units must not exceed max-units", fn, "min-units", "max-units")
+// will report `min-units, max-units: min-units must not exceed max-units`
+// when fn returns false.
+func Predicate(message string, check func(m map[string]interface{}) bool, fields ...string) Constraint {
+	return predicateC{message, check, fields}
+}
+
+type predicateC struct {
+	message string
+	check   func(m map[string]interface{}) bool
+	fields  []string
+}
+
+func (c predicateC) Check(m map[string]interface{}, path []string) error {
+	if c.check(m) {
+		return nil
+	}
+	return fieldsError{path, c.fields, c.message}
+}
+
+// isPresent reports whether the field is set to a non-nil value in the
+// coerced map.
+func isPresent(m map[string]interface{}, field string) bool {
+	v, ok := m[field]
+	return ok && v != nil
+}
+
+func presentFields(m map[string]interface{}, fields []string) []string {
+	var present []string
+	for _, f := range fields {
+		if isPresent(m, f) {
+			present = append(present, f)
+		}
+	}
+	return present
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestRequiredTogether(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"user":     schema.String(),
+		"password": schema.String(),
+	}, schema.Defaults{
+		"user":     schema.Omit,
+		"password": schema.Omit,
+	}, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{schema.RequiredTogether("user", "password")},
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{})
+
+	out, err = sch.Coerce(map[string]interface{}{"user": "u", "password": "p"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"user": "u", "password": "p"})
+
+	out, err = sch.Coerce(map[string]interface{}{"user": "u"}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\.user, <path>\.password: must be specified together`)
+
+	out, err = sch.Coerce(map[string]interface{}{"password": "p"}, nil)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `user, password: must be specified together`)
+}
+
+func (s *S) TestMutuallyExclusive(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"a": schema.Int(),
+		"b": schema.Int(),
+		"c": schema.Int(),
+	}, schema.Defaults{
+		"a": schema.Omit,
+		"b": schema.Omit,
+		"c": schema.Omit,
+	}, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{schema.MutuallyExclusive("a", "b", "c")},
+	})
+
+	_, err := sch.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.IsNil)
+
+	_, err = sch.Coerce(map[string]interface{}{"b": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+
+	_, err = sch.Coerce(map[string]interface{}{"a": 1, "c": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a, <path>\.c: only one may be specified`)
+}
+
+func (s *S) TestExactlyOneOf(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"password": schema.String(),
+		"key":      schema.String(),
+	}, schema.Defaults{
+		"password": schema.Omit,
+		"key":      schema.Omit,
+	}, schema.FieldMapOptions{
+		Strict:      true,
+		Constraints: []schema.Constraint{schema.ExactlyOneOf("password", "key")},
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"key": "k"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"key": "k"})
+
+	_, err = sch.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.password, <path>\.key: exactly one must be specified`)
+
+	_, err = sch.Coerce(map[string]interface{}{"password": "p", "key": "k"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.password, <path>\.key: exactly one must be specified`)
+
+	// Strict checking happens before any constraint.
+	_, err = sch.Coerce(map[string]interface{}{"token": "t"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "token" \(value "t"\)`)
+}
+
+func (s *S) TestRequiredIf(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"tls":  schema.Bool(),
+		"cert": schema.String(),
+		"key":  schema.String(),
+	}, schema.Defaults{
+		"tls":  false,
+		"cert": schema.Omit,
+		"key":  schema.Omit,
+	}, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{schema.RequiredIf("tls", true, "cert", "key")},
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"tls": false})
+
+	_, err = sch.Coerce(map[string]interface{}{"tls": "true", "cert": "c", "key": "k"}, aPath)
+	c.Assert(err, gc.IsNil)
+
+	_, err = sch.Coerce(map[string]interface{}{"tls": true, "key": "k"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.cert: required when tls is true`)
+
+	_, err = sch.Coerce(map[string]interface{}{"tls": true}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.cert, <path>\.key: required when tls is true`)
+}
+
+func (s *S) TestPredicate(c *gc.C) {
+	unitsInOrder := func(m map[string]interface{}) bool {
+		return m["min-units"].(int64) <= m["max-units"].(int64)
+	}
+	sch := schema.FieldMapWith(schema.Fields{
+		"min-units": schema.Int(),
+		"max-units": schema.Int(),
+	}, schema.Defaults{
+		"min-units": 1,
+		"max-units": 1,
+	}, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{
+			schema.Predicate("min-units must not exceed max-units", unitsInOrder, "min-units", "max-units"),
+		},
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"max-units": 3}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"min-units": int64(1), "max-units": int64(3)})
+
+	_, err = sch.Coerce(map[string]interface{}{"min-units": 3}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.min-units, <path>\.max-units: min-units must not exceed max-units`)
+
+	// Field errors are reported before constraints are checked.
+	_, err = sch.Coerce(map[string]interface{}{"min-units": "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.min-units: expected int, got string\("x"\)`)
+}
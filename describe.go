@@ -0,0 +1,312 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies the kind of checker a Description is about.
+type Kind string
+
+const (
+	// KindAny describes the checker returned by Any.
+	KindAny Kind = "any"
+
+	// KindOneOf describes the checker returned by OneOf.
+	KindOneOf Kind = "oneof"
+
+	// KindConst describes the checker returned by Const.
+	KindConst Kind = "const"
+
+	// KindNil describes the checker returned by Nil.
+	KindNil Kind = "nil"
+
+	// KindBool describes the checker returned by Bool.
+	KindBool Kind = "bool"
+
+	// KindInt describes the checker returned by Int.
+	KindInt Kind = "int"
+
+	// KindUint describes the checker returned by Uint.
+	KindUint Kind = "uint"
+
+	// KindForceInt describes the checker returned by ForceInt.
+	KindForceInt Kind = "forceint"
+
+	// KindForceUint describes the checker returned by ForceUint.
+	KindForceUint Kind = "forceuint"
+
+	// KindFloat describes the checker returned by Float.
+	KindFloat Kind = "float"
+
+	// KindString describes the checker returned by String.
+	KindString Kind = "string"
+
+	// KindNonEmptyString describes the checker returned by
+	// NonEmptyString.
+	KindNonEmptyString Kind = "nonemptystring"
+
+	// KindStringified describes the checker returned by Stringified.
+	KindStringified Kind = "stringified"
+
+	// KindURL describes the checker returned by URL.
+	KindURL Kind = "url"
+
+	// KindRegexp describes the checker returned by SimpleRegexp.
+	KindRegexp Kind = "regexp"
+
+	// KindUUID describes the checker returned by UUID.
+	KindUUID Kind = "uuid"
+
+	// KindSize describes the checker returned by Size.
+	KindSize Kind = "size"
+
+	// KindTime describes the checker returned by Time.
+	KindTime Kind = "time"
+
+	// KindTimeDuration describes the checker returned by
+	// TimeDuration.
+	KindTimeDuration Kind = "duration"
+
+	// KindList describes the checkers returned by List and ListWith.
+	KindList Kind = "list"
+
+	// KindSet describes the checker returned by Set.
+	KindSet Kind = "set"
+
+	// KindTuple describes the checkers returned by Tuple and
+	// TupleWith.
+	KindTuple Kind = "tuple"
+
+	// KindMap describes the checkers returned by Map and MapWith.
+	KindMap Kind = "map"
+
+	// KindStringMap describes the checkers returned by StringMap and
+	// StringMapWith.
+	KindStringMap Kind = "stringmap"
+
+	// KindFieldMap describes the checkers returned by FieldMap,
+	// StrictFieldMap and FieldMapWith.
+	KindFieldMap Kind = "fieldmap"
+
+	// KindFieldMapSet describes the checkers returned by FieldMapSet
+	// and FieldMapSetWith.
+	KindFieldMapSet Kind = "fieldmapset"
+
+	// KindFieldMapSwitch describes the checker returned by
+	// FieldMapSwitch.
+	KindFieldMapSwitch Kind = "fieldmapswitch"
+
+	// KindJSON describes the checker returned by JSONCompatible.
+	KindJSON Kind = "json"
+
+	// KindCustom is used for checkers that don't implement
+	// Descriptor.
+	KindCustom Kind = "custom"
+)
+
+// Description describes a Checker and the checkers it is made of.
+type Description struct {
+	// Kind identifies the checker.
+	Kind Kind
+
+	// Label holds a short human readable description of the values
+	// accepted by the checker, such as "list of int". Checkers
+	// converting numbers of any type are labelled by the type they
+	// convert to, such as "number as int" for ForceInt.
+	Label string
+
+	// Meta holds the metadata attached to the checker with WithMeta.
+	Meta Meta
+
+	// Nullable reports whether the checker was marked with Nullable.
+	Nullable bool
+
+	// Value holds the value accepted by a Const checker.
+	Value interface{}
+
+	// Key describes the checker for the keys of a Map.
+	Key *Description
+
+	// Elem describes the checker for the elements of a List or Set,
+	// the values of a Map or StringMap, the elements beyond the items
+	// of a Tuple, the additional keys of a FieldMap, or the checker
+	// wrapped by JSONCompatible.
+	Elem *Description
+
+	// Items describes the checkers for the elements of a Tuple.
+	Items []Description
+
+	// Options describes the options of OneOf and Stringified.
+	Options []Description
+
+	// Fields describes the fields of a FieldMap, sorted by name.
+	Fields []FieldDescription
+
+	// Selector holds the name of the selector field of a FieldMapSet
+	// or FieldMapSwitch.
+	Selector string
+
+	// Branches describes the branches of a FieldMapSet or
+	// FieldMapSwitch.
+	Branches []Branch
+
+	// MinLen and MaxLen hold the minimum and maximum number of
+	// elements of a List, Set or Tuple, and MaxKeys the maximum
+	// number of keys of a Map or StringMap. A zero maximum means
+	// there is no limit. They are also described in Constraints.
+	MinLen  int
+	MaxLen  int
+	MaxKeys int
+
+	// Constraints holds human readable descriptions of further
+	// restrictions on the accepted values, such as "unique".
+	Constraints []string
+
+	// checker holds the described checker.
+	checker Checker
+}
+
+// FieldDescription describes a field of a FieldMap.
+type FieldDescription struct {
+	// Name holds the name of the field.
+	Name string
+
+	// Checker describes the checker for the field value.
+	Checker Description
+
+	// Required reports whether the field must be present: it is
+	// either marked with Required, or it has no default, it is not
+	// marked with Optional, and its checker is not described as
+	// accepting nil, as Any, Nil and Nullable checkers are.
+	Required bool
+
+	// HasDefault reports whether the field has a default value.
+	HasDefault bool
+
+	// Default holds the default value of the field, if any.
+	Default interface{}
+
+	// Aliases holds the alternative names of the field, sorted.
+	Aliases []string
+
+	// Deprecated reports whether the field is deprecated, and
+	// DeprecationMessage holds the message explaining it.
+	Deprecated         bool
+	DeprecationMessage string
+}
+
+// Branch describes a branch of a FieldMapSet or FieldMapSwitch.
+type Branch struct {
+	// Value holds the selector value choosing the branch, when it is
+	// known. It is nil for the fallback branch of a FieldMapSwitch.
+	Value interface{}
+
+	// HasValue reports whether Value is known.
+	HasValue bool
+
+	// Checker describes the checker of the branch.
+	Checker Description
+}
+
+// Descriptor is implemented by checkers that can describe themselves.
+type Descriptor interface {
+	Describe() Description
+}
+
+// Describe returns a description of the given checker. Checkers that
+// don't implement Descriptor are described with KindCustom and their
+// Go type as label.
+func Describe(c Checker) Description {
+	var d Description
+	if desc, ok := c.(Descriptor); ok {
+		d = desc.Describe()
+	} else if w, ok := c.(wrapper); ok {
+		d = Describe(w.unwrap())
+	} else {
+		d = Description{Kind: KindCustom, Label: fmt.Sprintf("%T", c)}
+	}
+	d.checker = c
+	return d
+}
+
+// describePtr returns a pointer to the description of c.
+func describePtr(c Checker) *Description {
+	d := Describe(c)
+	return &d
+}
+
+// describeAll returns the descriptions of all the checkers.
+func describeAll(checkers []Checker) []Description {
+	ds := make([]Description, len(checkers))
+	for i, c := range checkers {
+		ds[i] = Describe(c)
+	}
+	return ds
+}
+
+// joinLabels returns the labels of the descriptions joined by sep.
+func joinLabels(ds []Description, sep string) string {
+	labels := make([]string, len(ds))
+	for i, d := range ds {
+		labels[i] = d.Label
+	}
+	return strings.Join(labels, sep)
+}
+
+// describeConstraint returns a human readable description of a
+// FieldMap constraint.
+func describeConstraint(c Constraint) string {
+	switch c := c.(type) {
+	case requiredTogetherC:
+		return "required together: " + strings.Join(c.fields, ", ")
+	case mutuallyExclusiveC:
+		return "mutually exclusive: " + strings.Join(c.fields, ", ")
+	case exactlyOneOfC:
+		return "exactly one of: " + strings.Join(c.fields, ", ")
+	case requiredIfC:
+		return fmt.Sprintf("required when %s is %#v: %s", c.field, c.value, strings.Join(c.required, ", "))
+	case predicateC:
+		return c.message
+	}
+	return fmt.Sprintf("%T", c)
+}
+
+// boundConstraints returns the human readable descriptions of the
+// length and key count bounds held in d.
+func boundConstraints(d Description) []string {
+	var constraints []string
+	if d.MinLen > 0 {
+		constraints = append(constraints, "at least "+elements(d.MinLen))
+	}
+	if d.MaxLen > 0 {
+		constraints = append(constraints, "at most "+elements(d.MaxLen))
+	}
+	if d.MaxKeys > 0 {
+		constraints = append(constraints, "at most "+keysLabel(d.MaxKeys))
+	}
+	return constraints
+}
+
+// acceptsNil reports whether the checker described by d accepts nil,
+// judging from its description only.
+func acceptsNil(d Description) bool {
+	switch d.Kind {
+	case KindAny, KindNil:
+		return true
+	case KindConst:
+		return d.Value == nil
+	case KindOneOf:
+		for _, option := range d.Options {
+			if acceptsNil(option) {
+				return true
+			}
+		}
+	case KindJSON:
+		return acceptsNil(*d.Elem)
+	}
+	return d.Nullable
+}
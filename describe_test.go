@@ -0,0 +1,176 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestDescribeSimple(c *gc.C) {
+	for _, test := range []struct {
+		checker schema.Checker
+		kind    schema.Kind
+		label   string
+	}{
+		{schema.Any(), schema.KindAny, "any value"},
+		{schema.Bool(), schema.KindBool, "bool"},
+		{schema.Int(), schema.KindInt, "int"},
+		{schema.Uint(), schema.KindUint, "uint"},
+		{schema.ForceInt(), schema.KindForceInt, "number as int"},
+		{schema.ForceUint(), schema.KindForceUint, "number as uint"},
+		{schema.String(), schema.KindString, "string"},
+		{schema.URL(), schema.KindURL, "url"},
+		{schema.Time(), schema.KindTime, "time"},
+		{schema.TimeDuration(), schema.KindTimeDuration, "duration"},
+		{schema.Const("foo"), schema.KindConst, `"foo"`},
+		{schema.List(schema.Int()), schema.KindList, "list of int"},
+		{schema.Set(schema.String()), schema.KindSet, "set of string"},
+		{schema.Map(schema.String(), schema.Int()), schema.KindMap, "map of string to int"},
+		{schema.StringMap(schema.Bool()), schema.KindStringMap, "map of string to bool"},
+		{schema.OneOf(schema.Int(), schema.String()), schema.KindOneOf, "one of int, string"},
+		{schema.Tuple(schema.String(), schema.Int()), schema.KindTuple, "tuple of string, int"},
+		{schema.JSONCompatible(schema.List(schema.Int())), schema.KindJSON, "list of int"},
+		{schema.Required(schema.Int()), schema.KindInt, "int"},
+	} {
+		d := schema.Describe(test.checker)
+		c.Check(d.Kind, gc.Equals, test.kind)
+		c.Check(d.Label, gc.Equals, test.label)
+	}
+}
+
+type customC struct{}
+
+func (customC) Coerce(v interface{}, path []string) (interface{}, error) {
+	return v, nil
+}
+
+func (s *S) TestDescribeCustom(c *gc.C) {
+	d := schema.Describe(customC{})
+	c.Assert(d.Kind, gc.Equals, schema.KindCustom)
+	c.Assert(d.Label, gc.Equals, "schema_test.customC")
+}
+
+func (s *S) TestDescribeNullable(c *gc.C) {
+	d := schema.Describe(schema.Nullable(schema.String()))
+	c.Assert(d.Kind, gc.Equals, schema.KindString)
+	c.Assert(d.Nullable, gc.Equals, true)
+}
+
+func (s *S) TestDescribeList(c *gc.C) {
+	d := schema.Describe(schema.ListWith(schema.String(), schema.ListOptions{
+		MinLen: 1,
+		MaxLen: 3,
+		Unique: true,
+	}))
+	c.Assert(d.Elem.Kind, gc.Equals, schema.KindString)
+	c.Assert(d.MinLen, gc.Equals, 1)
+	c.Assert(d.MaxLen, gc.Equals, 3)
+	c.Assert(d.Constraints, gc.DeepEquals, []string{"at least 1 element", "at most 3 elements", "unique"})
+
+	d = schema.Describe(schema.StringMapWith(schema.Int(), schema.StringMapOptions{MaxKeys: 2}))
+	c.Assert(d.MaxKeys, gc.Equals, 2)
+	c.Assert(d.Constraints, gc.DeepEquals, []string{"at most 2 keys"})
+}
+
+// panicC panics when used to coerce a value.
+type panicC struct{}
+
+func (panicC) Coerce(v interface{}, path []string) (interface{}, error) {
+	panic("Coerce called")
+}
+
+func (s *S) TestDescribeRequiredWithoutCoercion(c *gc.C) {
+	d := schema.Describe(schema.FieldMap(schema.Fields{
+		"any":      schema.Any(),
+		"nil":      schema.OneOf(schema.Nil(""), schema.Int()),
+		"nullable": schema.Nullable(schema.Int()),
+		"custom":   panicC{},
+		"optional": schema.Optional(panicC{}),
+	}, nil))
+	required := make(map[string]bool)
+	for _, f := range d.Fields {
+		required[f.Name] = f.Required
+	}
+	c.Assert(required, gc.DeepEquals, map[string]bool{
+		"any":      false,
+		"nil":      false,
+		"nullable": false,
+		"custom":   true,
+		"optional": false,
+	})
+}
+
+func (s *S) TestDescribeFieldMap(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"name":    schema.String(),
+		"port":    schema.Int(),
+		"tags":    schema.List(schema.String()),
+		"comment": schema.Nullable(schema.String()),
+		"owner":   schema.Required(schema.String()),
+	}, schema.Defaults{
+		"port": 80,
+		"tags": schema.Omit,
+	}, schema.FieldMapOptions{
+		Strict:      true,
+		Aliases:     map[string]string{"n": "name"},
+		Deprecated:  map[string]string{"comment": "use tags"},
+		Constraints: []schema.Constraint{schema.MutuallyExclusive("comment", "tags")},
+	})
+	d := schema.Describe(sch)
+	c.Assert(d.Kind, gc.Equals, schema.KindFieldMap)
+	c.Assert(d.Constraints, gc.DeepEquals, []string{
+		"no unknown keys",
+		"mutually exclusive: comment, tags",
+	})
+
+	names := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		names[i] = f.Name
+	}
+	c.Assert(names, gc.DeepEquals, []string{"comment", "name", "owner", "port", "tags"})
+
+	comment, name, owner, port, tags := d.Fields[0], d.Fields[1], d.Fields[2], d.Fields[3], d.Fields[4]
+	c.Check(comment.Required, gc.Equals, false)
+	c.Check(comment.Checker.Nullable, gc.Equals, true)
+	c.Check(comment.Deprecated, gc.Equals, true)
+	c.Check(comment.DeprecationMessage, gc.Equals, "use tags")
+	c.Check(name.Required, gc.Equals, true)
+	c.Check(name.Aliases, gc.DeepEquals, []string{"n"})
+	c.Check(owner.Required, gc.Equals, true)
+	c.Check(port.Required, gc.Equals, false)
+	c.Check(port.HasDefault, gc.Equals, true)
+	c.Check(port.Default, gc.Equals, 80)
+	c.Check(tags.Required, gc.Equals, false)
+	c.Check(tags.HasDefault, gc.Equals, false)
+	c.Check(tags.Checker.Label, gc.Equals, "list of string")
+}
+
+func (s *S) TestDescribeFieldMapSwitch(c *gc.C) {
+	sch := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"b": schema.FieldMap(schema.Fields{"type": schema.Const("b")}, nil),
+		"a": schema.FieldMap(schema.Fields{"type": schema.Const("a")}, nil),
+	}, schema.FieldMap(schema.Fields{"type": schema.String()}, nil))
+	d := schema.Describe(sch)
+	c.Assert(d.Kind, gc.Equals, schema.KindFieldMapSwitch)
+	c.Assert(d.Selector, gc.Equals, "type")
+	c.Assert(d.Branches, gc.HasLen, 3)
+	c.Check(d.Branches[0].Value, gc.Equals, "a")
+	c.Check(d.Branches[1].Value, gc.Equals, "b")
+	c.Check(d.Branches[2].HasValue, gc.Equals, false)
+}
+
+func (s *S) TestDescribeFieldMapSet(c *gc.C) {
+	sch := schema.FieldMapSet("kind", []schema.Checker{
+		schema.FieldMap(schema.Fields{"kind": schema.Const("x")}, nil),
+		schema.FieldMap(schema.Fields{"kind": schema.String()}, nil),
+	})
+	d := schema.Describe(sch)
+	c.Assert(d.Kind, gc.Equals, schema.KindFieldMapSet)
+	c.Assert(d.Branches, gc.HasLen, 2)
+	c.Check(d.Branches[0].HasValue, gc.Equals, true)
+	c.Check(d.Branches[0].Value, gc.Equals, "x")
+	c.Check(d.Branches[1].HasValue, gc.Equals, false)
+}
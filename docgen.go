@@ -0,0 +1,226 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown returns a Markdown table documenting the fields accepted by
+// checker, one row per field path, with its type, whether it is
+// required, its default, its allowed values and its description.
+//
+// Fields of nested FieldMaps are listed with paths such as
+// "db.host", elements of lists with paths such as "units[].name",
+// and values of maps with paths such as "labels.*".
+func Markdown(checker Checker) string {
+	var buf strings.Builder
+	buf.WriteString("| Field | Type | Required | Default | Allowed values | Description |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, row := range docRows(Describe(checker), "", nil) {
+		fmt.Fprintf(&buf, "| `%s` | %s | %s | %s | %s | %s |\n",
+			row.path,
+			markdownCell(row.label),
+			yesNo(row.required),
+			markdownCell(row.dflt),
+			markdownCell(row.allowed),
+			markdownCell(row.doc),
+		)
+	}
+	return buf.String()
+}
+
+// PlainText returns a plain text description of the fields accepted by
+// checker, suitable for command line help. It holds the same
+// information as Markdown.
+func PlainText(checker Checker) string {
+	var buf strings.Builder
+	for i, row := range docRows(Describe(checker), "", nil) {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%s (%s", row.path, row.label)
+		if row.required {
+			buf.WriteString(", required")
+		}
+		buf.WriteString(")\n")
+		if row.doc != "" {
+			fmt.Fprintf(&buf, "    %s\n", strings.Replace(row.doc, "\n", "\n    ", -1))
+		}
+		if row.dflt != "" {
+			fmt.Fprintf(&buf, "    Default: %s\n", row.dflt)
+		}
+		if row.allowed != "" {
+			fmt.Fprintf(&buf, "    Allowed values: %s\n", row.allowed)
+		}
+	}
+	return buf.String()
+}
+
+// docRow holds the documentation of a single field path.
+type docRow struct {
+	path     string
+	label    string
+	required bool
+	dflt     string
+	allowed  string
+	doc      string
+}
+
+// docRows appends to rows the documentation of the fields found in the
+// value described by d, found at the given path.
+func docRows(d Description, path string, rows []docRow) []docRow {
+	switch d.Kind {
+	case KindFieldMap:
+		for _, f := range d.Fields {
+			fpath := joinDocPath(path, f.Name)
+			row := docRow{
+				path:     fpath,
+				label:    f.Checker.Label,
+				required: f.Required,
+				allowed:  allowedValues(f.Checker),
+				doc:      fieldDoc(f.Checker.Meta),
+			}
+			if f.HasDefault && !f.Checker.Meta.Secret {
+				row.dflt = docValue(f.Default)
+			}
+			if f.Deprecated {
+				msg := "Deprecated"
+				if f.DeprecationMessage != "" {
+					msg += ": " + f.DeprecationMessage
+				}
+				row.doc = joinDoc(msg+".", row.doc)
+			}
+			rows = append(rows, row)
+			rows = docRows(f.Checker, fpath, rows)
+		}
+		if d.Elem != nil {
+			rows = append(rows, docRow{
+				path:  joinDocPath(path, "*"),
+				label: d.Elem.Label,
+				doc:   fieldDoc(d.Elem.Meta),
+			})
+			rows = docRows(*d.Elem, joinDocPath(path, "*"), rows)
+		}
+	case KindList, KindSet:
+		rows = docRows(*d.Elem, path+"[]", rows)
+	case KindMap, KindStringMap:
+		rows = docRows(*d.Elem, joinDocPath(path, "*"), rows)
+	case KindJSON:
+		rows = docRows(*d.Elem, path, rows)
+	case KindFieldMapSet, KindFieldMapSwitch:
+		rows = branchDocRows(d, path, rows)
+	}
+	return rows
+}
+
+// branchDocRows appends to rows the documentation of the fields of
+// every branch of a FieldMapSet or FieldMapSwitch. The selector field
+// is documented once, and the other fields are annotated with the
+// selector value choosing their branch, when it is known.
+func branchDocRows(d Description, path string, rows []docRow) []docRow {
+	selectorPath := joinDocPath(path, d.Selector)
+	selector := -1
+	var values []string
+	for _, b := range d.Branches {
+		when := ""
+		if b.HasValue {
+			values = append(values, docValue(b.Value))
+			when = fmt.Sprintf("Only when %s is %s.", d.Selector, docValue(b.Value))
+		}
+		for _, row := range docRows(b.Checker, path, nil) {
+			if row.path != selectorPath {
+				row.doc = joinDoc(when, row.doc)
+				rows = append(rows, row)
+				continue
+			}
+			if selector == -1 {
+				selector = len(rows)
+				rows = append(rows, row)
+			}
+		}
+	}
+	if selector != -1 && len(values) == len(d.Branches) {
+		rows[selector].allowed = strings.Join(values, ", ")
+	}
+	return rows
+}
+
+// fieldDoc returns the documentation of a field with the given
+// metadata.
+func fieldDoc(meta Meta) string {
+	doc := meta.Description
+	if meta.Title != "" {
+		doc = joinDoc(meta.Title+".", doc)
+	}
+	if meta.Secret {
+		doc = joinDoc(doc, "Secret.")
+	}
+	if len(meta.Examples) > 0 {
+		examples := make([]string, len(meta.Examples))
+		for i, example := range meta.Examples {
+			examples[i] = docValue(example)
+		}
+		doc = joinDoc(doc, "Examples: "+strings.Join(examples, ", ")+".")
+	}
+	if meta.Since != "" {
+		doc = joinDoc(doc, "Since "+meta.Since+".")
+	}
+	return doc
+}
+
+// allowedValues returns the values accepted by the checker described
+// by d, when it only accepts a fixed set of values.
+func allowedValues(d Description) string {
+	switch d.Kind {
+	case KindConst:
+		return docValue(d.Value)
+	case KindOneOf:
+		values := make([]string, len(d.Options))
+		for i, option := range d.Options {
+			if option.Kind != KindConst {
+				return ""
+			}
+			values[i] = docValue(option.Value)
+		}
+		return strings.Join(values, ", ")
+	}
+	return ""
+}
+
+func docValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
+
+func joinDocPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func joinDoc(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + " " + b
+}
+
+// markdownCell escapes s so that it can be used in a Markdown table
+// cell.
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
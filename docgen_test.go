@@ -0,0 +1,105 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+var docSchema = schema.FieldMap(schema.Fields{
+	"name": schema.Documented(schema.String(), "The application name."),
+	"mode": schema.Documented(
+		schema.OneOf(schema.Const("fast"), schema.Const("safe")),
+		"How units are\nupdated.",
+	),
+	"units": schema.List(schema.FieldMap(schema.Fields{
+		"id": schema.Documented(schema.Int(), "The unit | number."),
+	}, nil)),
+}, schema.Defaults{
+	"mode":  "safe",
+	"units": schema.Omit,
+})
+
+func (s *S) TestDocumented(c *gc.C) {
+	sch := schema.Documented(schema.Required(schema.Int()), "A count.")
+	out, err := sch.Coerce(42, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, int64(42))
+
+	d := schema.Describe(sch)
+	c.Assert(d.Kind, gc.Equals, schema.KindInt)
+	c.Assert(d.Meta.Description, gc.Equals, "A count.")
+
+	fields := schema.Describe(schema.FieldMap(schema.Fields{"n": sch}, nil)).Fields
+	c.Assert(fields[0].Required, gc.Equals, true)
+}
+
+func (s *S) TestMarkdown(c *gc.C) {
+	c.Assert(schema.Markdown(docSchema), gc.Equals, ""+
+		"| Field | Type | Required | Default | Allowed values | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| `mode` | one of \"fast\", \"safe\" | no | \"safe\" | \"fast\", \"safe\" | How units are<br>updated. |\n"+
+		"| `name` | string | yes |  |  | The application name. |\n"+
+		"| `units` | list of map | no |  |  |  |\n"+
+		"| `units[].id` | int | yes |  |  | The unit \\| number. |\n")
+}
+
+func (s *S) TestPlainText(c *gc.C) {
+	c.Assert(schema.PlainText(docSchema), gc.Equals, ""+
+		"mode (one of \"fast\", \"safe\")\n"+
+		"    How units are\n"+
+		"    updated.\n"+
+		"    Default: \"safe\"\n"+
+		"    Allowed values: \"fast\", \"safe\"\n"+
+		"\n"+
+		"name (string, required)\n"+
+		"    The application name.\n"+
+		"\n"+
+		"units (list of map)\n"+
+		"\n"+
+		"units[].id (int, required)\n"+
+		"    The unit | number.\n")
+}
+
+func (s *S) TestMarkdownFieldMapSwitch(c *gc.C) {
+	sch := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"file": schema.FieldMap(schema.Fields{
+			"type": schema.Const("file"),
+			"path": schema.String(),
+		}, nil),
+		"url": schema.FieldMap(schema.Fields{
+			"type": schema.Const("url"),
+			"url":  schema.URL(),
+		}, nil),
+	}, nil)
+	c.Assert(schema.Markdown(sch), gc.Equals, ""+
+		"| Field | Type | Required | Default | Allowed values | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| `path` | string | yes |  |  | Only when type is \"file\". |\n"+
+		"| `type` | \"file\" | yes |  | \"file\", \"url\" |  |\n"+
+		"| `url` | url | yes |  |  | Only when type is \"url\". |\n")
+}
+
+func (s *S) TestPlainTextDeprecated(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"old":   schema.Int(),
+		"units": schema.Int(),
+	}, schema.Defaults{
+		"old":   schema.Omit,
+		"units": schema.Omit,
+	}, schema.FieldMapOptions{
+		Deprecated: map[string]string{
+			"old":   "",
+			"units": `use "scale" instead`,
+		},
+	})
+	c.Assert(schema.PlainText(sch), gc.Equals, ""+
+		"old (int)\n"+
+		"    Deprecated.\n"+
+		"\n"+
+		"units (int)\n"+
+		"    Deprecated: use \"scale\" instead.\n")
+}
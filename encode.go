@@ -0,0 +1,50 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+// Encoder is implemented by checkers that can convert the values they
+// coerce back into their canonical serialized form.
+//
+// The Uncoerce method is called recursively with a value coerced by the
+// checker, and returns plain data made of strings, numbers, booleans,
+// lists and maps that the checker accepts, coercing it back to an
+// equivalent value.
+type Encoder interface {
+	Uncoerce(v interface{}, path []string) (interface{}, error)
+}
+
+// Uncoerce converts v, a value coerced by c, back into plain data that
+// can be serialized and coerced by c again, so that configuration can
+// be round-tripped. For instance the *url.URL produced by URL is
+// converted back to a string, and the fields of a FieldMap that are set
+// to their default value are omitted.
+//
+// Values produced by checkers that don't implement Encoder are returned
+// unchanged.
+func Uncoerce(c Checker, v interface{}) (interface{}, error) {
+	return uncoerce(c, v, nil)
+}
+
+// hasEncoder reports whether the checker wrapped by c, or c itself if
+// it is not a wrapper, implements Encoder.
+func hasEncoder(c Checker) bool {
+	for {
+		w, ok := c.(wrapper)
+		if !ok {
+			_, ok := c.(Encoder)
+			return ok
+		}
+		c = w.unwrap()
+	}
+}
+
+func uncoerce(c Checker, v interface{}, path []string) (interface{}, error) {
+	if e, ok := c.(Encoder); ok {
+		return e.Uncoerce(v, path)
+	}
+	if w, ok := c.(wrapper); ok {
+		return uncoerce(w.unwrap(), v, path)
+	}
+	return v, nil
+}
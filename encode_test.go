@@ -0,0 +1,172 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	"net/url"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestUncoerceScalars(c *gc.C) {
+	tests := []struct {
+		checker schema.Checker
+		in      interface{}
+		out     interface{}
+	}{
+		{schema.Int(), int64(1), int64(1)},
+		{schema.String(), "s", "s"},
+		{schema.URL(), &url.URL{Scheme: "http", Host: "example.com", Path: "/a"}, "http://example.com/a"},
+		{schema.Time(), time.Date(2016, 1, 2, 3, 4, 5, 6, time.UTC), "2016-01-02T03:04:05.000000006Z"},
+		{schema.Time(), time.Time{}, ""},
+		{schema.TimeDuration(), 90 * time.Second, "1m30s"},
+		{schema.Size(), uint64(1536), "1536M"},
+		{schema.Size(), uint64(2048), "2G"},
+		{schema.Size(), uint64(0), "0M"},
+		{schema.Nullable(schema.URL()), nil, nil},
+		{schema.OneOf(schema.Int(), schema.URL()), &url.URL{Scheme: "http", Host: "a"}, "http://a"},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %#v", i, test.in)
+		out, err := schema.Uncoerce(test.checker, test.in)
+		c.Assert(err, gc.IsNil)
+		c.Assert(out, gc.DeepEquals, test.out)
+
+		// The result coerces back to the original value.
+		back, err := test.checker.Coerce(out, nil)
+		c.Assert(err, gc.IsNil)
+		c.Assert(back, gc.DeepEquals, test.in)
+	}
+}
+
+func (s *S) TestUncoerceErrors(c *gc.C) {
+	sch := schema.StringMap(schema.List(schema.URL()))
+	_, err := schema.Uncoerce(sch, map[string]interface{}{"a": []interface{}{"http://a"}})
+	c.Assert(err, gc.ErrorMatches, `a\[0\]: expected \*url.URL, got string\("http://a"\)`)
+
+	_, err = schema.Uncoerce(schema.TimeDuration(), "1s")
+	c.Assert(err, gc.ErrorMatches, `expected time.Duration, got string\("1s"\)`)
+}
+
+func (s *S) TestUncoerceFieldMap(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"name":     schema.Required(schema.String()),
+		"endpoint": schema.URL(),
+		"timeout":  schema.TimeDuration(),
+		"retries":  schema.Int(),
+		"ports":    schema.List(schema.Int()),
+		"lookup":   schema.Map(schema.String(), schema.Size()),
+		"type":     schema.Const("x"),
+	}, schema.Defaults{
+		"name":     "default",
+		"endpoint": schema.Omit,
+		"timeout":  "30s",
+		"retries":  3,
+		"ports":    schema.Omit,
+		"lookup":   schema.Omit,
+		"type":     "x",
+	}, schema.FieldMapOptions{
+		UnknownField: "extras",
+	})
+	in := map[string]interface{}{
+		"name":     "default",
+		"endpoint": "https://example.com",
+		"timeout":  "1m",
+		"retries":  3,
+		"ports":    []interface{}{80, 443},
+		"lookup":   map[interface{}]interface{}{"a": "1G"},
+		"other":    "kept",
+	}
+	coerced, err := sch.Coerce(in, nil)
+	c.Assert(err, gc.IsNil)
+
+	out, err := schema.Uncoerce(sch, coerced)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"name":     "default",
+		"endpoint": "https://example.com",
+		"timeout":  "1m0s",
+		"ports":    []interface{}{int64(80), int64(443)},
+		"lookup":   map[interface{}]interface{}{"a": "1G"},
+		"other":    "kept",
+	})
+
+	again, err := sch.Coerce(out, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(again, gc.DeepEquals, coerced)
+}
+
+func (s *S) TestUncoerceFieldMapSet(c *gc.C) {
+	sch := schema.FieldMapSet("type", []schema.Checker{
+		schema.FieldMap(schema.Fields{
+			"type": schema.Const("url"),
+			"url":  schema.URL(),
+		}, nil),
+		schema.FieldMap(schema.Fields{
+			"type":    schema.Const("timeout"),
+			"timeout": schema.TimeDuration(),
+		}, nil),
+	})
+	coerced, err := sch.Coerce(map[string]interface{}{"type": "timeout", "timeout": "1s"}, nil)
+	c.Assert(err, gc.IsNil)
+
+	out, err := schema.Uncoerce(sch, coerced)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "timeout", "timeout": "1s"})
+
+	_, err = schema.Uncoerce(sch, map[string]interface{}{"type": "other"})
+	c.Assert(err, gc.ErrorMatches, `type: expected supported selector \("url", "timeout"\), got string\("other"\)`)
+
+	swch := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"url": schema.FieldMap(schema.Fields{
+			"type": schema.String(),
+			"url":  schema.URL(),
+		}, nil),
+	}, nil)
+	out, err = schema.Uncoerce(swch, map[string]interface{}{"type": "url", "url": &url.URL{Scheme: "http", Host: "a"}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "url", "url": "http://a"})
+}
+
+type panickyC struct{}
+
+func (panickyC) Coerce(v interface{}, path []string) (interface{}, error) {
+	if v == "boom" {
+		panic("boom")
+	}
+	return v, nil
+}
+
+type lossyC struct{}
+
+func (lossyC) Coerce(v interface{}, path []string) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return schema.String().Coerce(v, path)
+	}
+	return s + "!", nil
+}
+
+func (s *S) TestCheckCoerce(c *gc.C) {
+	c.Assert(schema.CheckCoerce(schema.URL(), "http://a/b%22"), gc.IsNil)
+	c.Assert(schema.CheckCoerce(schema.Size(), "1.5G"), gc.IsNil)
+
+	// Invalid input is not reported.
+	c.Assert(schema.CheckCoerce(schema.Int(), "x"), gc.IsNil)
+
+	err := schema.CheckCoerce(panickyC{}, "boom")
+	c.Assert(err, gc.ErrorMatches, `panic while coercing "boom": boom`)
+
+	err = schema.CheckCoerce(lossyC{}, "a")
+	c.Assert(err, gc.ErrorMatches, `"a!" coerced from "a" uncoerces to "a!", but then to "a!!"`)
+}
+
+func (s *S) TestFuzzCoerce(c *gc.C) {
+	c.Assert(schema.FuzzCoerce(schema.List(schema.Int()), []byte(`["1", 2]`)), gc.IsNil)
+	c.Assert(schema.FuzzCoerce(panickyC{}, []byte(`"boom"`)), gc.ErrorMatches, `panic while coercing "boom": boom`)
+	c.Assert(schema.FuzzCoerce(panickyC{}, []byte(`boom`)), gc.ErrorMatches, `panic while coercing "boom": boom`)
+}
@@ -5,6 +5,7 @@ package schema
 
 import (
 	"fmt"
+	"strings"
 )
 
 type error_ struct {
@@ -21,6 +22,12 @@ func (e error_) Error() string {
 	if e.got == nil {
 		return fmt.Sprintf("%sexpected %s, got nothing", path, e.want)
 	}
+	if r, ok := e.got.(redacted); ok {
+		if r.typ != "" {
+			return fmt.Sprintf("%sexpected %s, got %s(%s)", path, e.want, r.typ, Redacted)
+		}
+		return fmt.Sprintf("%sexpected %s, got %s", path, e.want, Redacted)
+	}
 	return fmt.Sprintf("%sexpected %s, got %T(%#v)", path, e.want, e.got, e.got)
 }
 
@@ -28,3 +35,79 @@ func parseError(path []string, expected string, err error) error {
 	prefix := pathAsPrefix(path)
 	return fmt.Errorf("%sconversion to %s: %s", prefix, expected, err.Error())
 }
+
+// fieldsError is returned when a Constraint is violated. Its message is
+// prefixed with the full path of every field involved.
+type fieldsError struct {
+	path   []string
+	fields []string
+	msg    string
+}
+
+func (e fieldsError) Error() string {
+	if len(e.fields) == 0 {
+		return pathAsPrefix(e.path) + e.msg
+	}
+	paths := make([]string, len(e.fields))
+	for i, f := range e.fields {
+		paths[i] = fieldPath(e.path, f)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(paths, ", "), e.msg)
+}
+
+// fieldPath returns the path of the named field within the map at path,
+// formatted as it would be in an error message.
+func fieldPath(path []string, field string) string {
+	fpath := make([]string, 0, len(path)+2)
+	fpath = append(fpath, path...)
+	fpath = append(fpath, ".", field)
+	return strings.TrimSuffix(pathAsPrefix(fpath), ": ")
+}
+
+// UnknownKeyError is returned by checkers that reject unknown map keys,
+// such as the one returned by StrictFieldMap.
+type UnknownKeyError struct {
+	// Path holds the path to the map containing the key.
+	Path []string
+
+	// Key holds the unknown key.
+	Key string
+
+	// Value holds the value associated with the key. It is hidden
+	// when the map is processed by a secret checker.
+	Value interface{}
+
+	// Suggestions holds the known keys closest to Key, if any are
+	// close enough to be a likely match.
+	Suggestions []string
+}
+
+func (e *UnknownKeyError) Error() string {
+	msg := fmt.Sprintf("%sunknown key %q (value %#v)", pathAsPrefix(e.Path), e.Key, e.Value)
+	switch len(e.Suggestions) {
+	case 0:
+		return msg
+	case 1:
+		return fmt.Sprintf("%s; did you mean %q?", msg, e.Suggestions[0])
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%s; did you mean one of %s?", msg, strings.Join(quoted, ", "))
+}
+
+// Warning describes a problem found while coercing a value that does
+// not prevent processing from succeeding.
+type Warning struct {
+	// Path holds the path to the value the warning is about.
+	Path []string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String returns the warning message prefixed by its path.
+func (w Warning) String() string {
+	return pathAsPrefix(w.Path) + w.Message
+}
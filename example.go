@@ -0,0 +1,125 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+)
+
+// ExampleOptions holds optional behaviour for ExampleWith.
+type ExampleOptions struct {
+	// AllFields causes the optional fields of FieldMaps to be
+	// included in the example, and lists and maps to hold an element
+	// even when they may be empty.
+	AllFields bool
+}
+
+// Example returns an example value accepted by c, made of plain data
+// that can be serialized. Defaults are used for the fields of a
+// FieldMap that have one, converted with the Encoder of the field
+// checker if it has one, the first example attached with WithMeta is
+// used when there is one, and minimal valid values are generated
+// otherwise. The first option of OneOf and the first branch of
+// FieldMapSet and FieldMapSwitch are used.
+//
+// The generated value is checked with c, and an error is returned if
+// it is not accepted, for instance because of a Predicate constraint
+// or of a custom checker the example can't satisfy.
+func Example(c Checker) (interface{}, error) {
+	return ExampleWith(c, ExampleOptions{})
+}
+
+// ExampleWith returns an example value accepted by c, as Example does,
+// with additional behaviour configured by opts.
+func ExampleWith(c Checker, opts ExampleOptions) (interface{}, error) {
+	v, err := example(c, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Coerce(v, nil); err != nil {
+		return nil, fmt.Errorf("generated example is not valid: %v", err)
+	}
+	return v, nil
+}
+
+// exampler is implemented by checkers that can generate an example of
+// the values they accept.
+type exampler interface {
+	example(opts ExampleOptions, path []string) (interface{}, error)
+}
+
+// exampleCandidates holds the values tried for checkers that can't
+// generate an example.
+var exampleCandidates = []interface{}{
+	nil,
+	"",
+	0,
+	false,
+	[]interface{}{},
+	map[string]interface{}{},
+}
+
+func example(c Checker, opts ExampleOptions, path []string) (interface{}, error) {
+	if e, ok := c.(exampler); ok {
+		return e.example(opts, path)
+	}
+	if w, ok := c.(wrapper); ok {
+		return example(w.unwrap(), opts, path)
+	}
+	for _, v := range exampleCandidates {
+		if _, err := c.Coerce(v, path); err == nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%scannot generate an example for %T", pathAsPrefix(path), c)
+}
+
+func (c metaC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	if len(c.meta.Examples) > 0 {
+		return c.meta.Examples[0], nil
+	}
+	return example(c.checker, opts, path)
+}
+
+// satisfy adjusts the fields in m, an example for a FieldMap, so that
+// it satisfies the constraint. Fields are added by calling add, which
+// returns an error if no example can be generated for the field.
+func satisfy(constraint Constraint, m map[string]interface{}, add func(field string) error) error {
+	switch c := constraint.(type) {
+	case requiredTogetherC:
+		if len(presentFields(m, c.fields)) == 0 {
+			return nil
+		}
+		for _, f := range c.fields {
+			if !isPresent(m, f) {
+				if err := add(f); err != nil {
+					return err
+				}
+			}
+		}
+	case mutuallyExclusiveC:
+		keepFirst(m, presentFields(m, c.fields))
+	case exactlyOneOfC:
+		present := presentFields(m, c.fields)
+		if len(present) == 0 && len(c.fields) > 0 {
+			return add(c.fields[0])
+		}
+		keepFirst(m, present)
+	case requiredIfC:
+		// The required fields are added whenever the field is
+		// present, which is harmless when its value differs.
+		if !isPresent(m, c.field) {
+			return nil
+		}
+		return satisfy(requiredTogetherC{append([]string{c.field}, c.required...)}, m, add)
+	}
+	return nil
+}
+
+// keepFirst removes from m all the given fields but the first one.
+func keepFirst(m map[string]interface{}, fields []string) {
+	for i := 1; i < len(fields); i++ {
+		delete(m, fields[i])
+	}
+}
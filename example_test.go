@@ -0,0 +1,185 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestExampleSimple(c *gc.C) {
+	for _, checker := range []schema.Checker{
+		schema.Any(),
+		schema.Bool(),
+		schema.Int(),
+		schema.Uint(),
+		schema.ForceInt(),
+		schema.ForceUint(),
+		schema.Float(),
+		schema.String(),
+		schema.NonEmptyString("name"),
+		schema.URL(),
+		schema.SimpleRegexp(),
+		schema.UUID(),
+		schema.Stringified(),
+		schema.Size(),
+		schema.Time(),
+		schema.TimeDuration(),
+		schema.Const("x"),
+		schema.Nil(""),
+		schema.OneOf(schema.Int(), schema.String()),
+		schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 2}),
+		schema.Tuple(schema.String(), schema.Int()),
+		schema.Map(schema.String(), schema.Int()),
+		schema.StringMap(schema.Int()),
+		schema.JSONCompatible(schema.Int()),
+		schema.Nullable(schema.Int()),
+		customC{},
+	} {
+		for _, all := range []bool{false, true} {
+			v, err := schema.ExampleWith(checker, schema.ExampleOptions{AllFields: all})
+			c.Check(err, gc.IsNil, gc.Commentf("%#v", checker))
+			_, err = checker.Coerce(v, nil)
+			c.Check(err, gc.IsNil, gc.Commentf("%#v", checker))
+		}
+	}
+}
+
+func (s *S) TestExampleConstrainedCollections(c *gc.C) {
+	for _, checker := range []schema.Checker{
+		schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 3, Unique: true}),
+		schema.ListWith(schema.StringMap(schema.Any()), schema.ListOptions{
+			MinLen: 2,
+			UniqueKey: func(elem interface{}) interface{} {
+				return len(elem.(map[string]interface{}))
+			},
+		}),
+		schema.ListWith(schema.Set(schema.String()), schema.ListOptions{MinLen: 2, Unique: true}),
+		schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{KeyPattern: regexp.MustCompile(`^[A-Z][a-z]+-[0-9]{2}$`)}),
+		schema.MapWith(schema.Int(), schema.Int(), schema.MapOptions{KeyPattern: regexp.MustCompile(`^x`)}),
+		schema.StringMapWith(schema.Int(), schema.StringMapOptions{KeyPattern: regexp.MustCompile(`^(foo|bar)\.[a-z]+$`)}),
+		schema.StringMapWith(schema.Int(), schema.StringMapOptions{
+			KeyPattern:   regexp.MustCompile(`^[a-z]+$`),
+			NormalizeKey: strings.ToLower,
+		}),
+	} {
+		v, err := schema.ExampleWith(checker, schema.ExampleOptions{AllFields: true})
+		c.Check(err, gc.IsNil, gc.Commentf("%#v", checker))
+		_, err = checker.Coerce(v, nil)
+		c.Check(err, gc.IsNil, gc.Commentf("%#v", checker))
+
+		// Examples are the same from one call to the next.
+		again, err := schema.ExampleWith(checker, schema.ExampleOptions{AllFields: true})
+		c.Check(err, gc.IsNil)
+		c.Check(again, gc.DeepEquals, v)
+	}
+
+	v, err := schema.ExampleWith(schema.StringMapWith(schema.Int(), schema.StringMapOptions{
+		KeyPattern: regexp.MustCompile(`^[0-9]+$`),
+	}), schema.ExampleOptions{AllFields: true})
+	c.Assert(err, gc.IsNil)
+	c.Assert(v, gc.HasLen, 1)
+	for k := range v.(map[string]interface{}) {
+		c.Assert(k, gc.Matches, `[0-9]+`)
+	}
+
+	// The optional entry is left out when no key can match.
+	v, err = schema.ExampleWith(schema.MapWith(schema.Int(), schema.Int(), schema.MapOptions{
+		KeyPattern: regexp.MustCompile(`^x`),
+	}), schema.ExampleOptions{AllFields: true})
+	c.Assert(err, gc.IsNil)
+	c.Assert(v, gc.DeepEquals, map[interface{}]interface{}{})
+
+	_, err = schema.Example(schema.ListWith(schema.Const("x"), schema.ListOptions{MinLen: 2, Unique: true}))
+	c.Assert(err, gc.ErrorMatches, `cannot generate 2 elements that are distinct`)
+}
+
+func (s *S) TestExampleFieldMap(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"name":    schema.String(),
+		"port":    schema.Int(),
+		"id":      schema.WithMeta(schema.UUID(), schema.Meta{Examples: []interface{}{"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}}),
+		"tags":    schema.List(schema.String()),
+		"comment": schema.Optional(schema.String()),
+		"old":     schema.Optional(schema.WithMeta(schema.String(), schema.Meta{Deprecated: true})),
+		"file":    schema.Optional(schema.String()),
+		"url":     schema.Optional(schema.URL()),
+		"timeout": schema.TimeDuration(),
+	}, schema.Defaults{
+		"port":    80,
+		"tags":    schema.Omit,
+		"timeout": 90 * time.Second,
+	}, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{schema.ExactlyOneOf("file", "url")},
+	})
+
+	v, err := schema.Example(sch)
+	c.Assert(err, gc.IsNil)
+	c.Assert(v, gc.DeepEquals, map[string]interface{}{
+		"name":    "",
+		"port":    80,
+		"id":      "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"file":    "",
+		"timeout": "1m30s",
+	})
+
+	v, err = schema.ExampleWith(sch, schema.ExampleOptions{AllFields: true})
+	c.Assert(err, gc.IsNil)
+	c.Assert(v, gc.DeepEquals, map[string]interface{}{
+		"name":    "",
+		"port":    80,
+		"id":      "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"tags":    []interface{}{""},
+		"comment": "",
+		"file":    "",
+		"timeout": "1m30s",
+	})
+}
+
+func (s *S) TestExampleBranches(c *gc.C) {
+	set := schema.FieldMapSet("type", []schema.Checker{
+		schema.FieldMap(schema.Fields{"type": schema.Const("a"), "a": schema.Int()}, nil),
+		schema.FieldMap(schema.Fields{"type": schema.Const("b")}, nil),
+	})
+	v, err := schema.Example(set)
+	c.Assert(err, gc.IsNil)
+	c.Assert(v, gc.DeepEquals, map[string]interface{}{"type": "a", "a": 0})
+
+	sw := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"b": schema.FieldMap(schema.Fields{"type": schema.Const("b")}, nil),
+		"a": schema.FieldMap(schema.Fields{"type": schema.Const("a")}, nil),
+	}, nil)
+	v, err = schema.Example(sw)
+	c.Assert(err, gc.IsNil)
+	c.Assert(v, gc.DeepEquals, map[string]interface{}{"type": "a"})
+}
+
+func (s *S) TestExampleInvalid(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"min": schema.Int(),
+		"max": schema.Int(),
+	}, nil, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{
+			schema.Predicate("min must be less than max", func(m map[string]interface{}) bool {
+				return m["min"].(int64) < m["max"].(int64)
+			}, "min", "max"),
+		},
+	})
+	_, err := schema.Example(sch)
+	c.Assert(err, gc.ErrorMatches, `generated example is not valid: min, max: min must be less than max`)
+
+	_, err = schema.Example(schema.FieldMap(schema.Fields{"x": schema.ListWith(unsatisfiableC{}, schema.ListOptions{MinLen: 1})}, nil))
+	c.Assert(err, gc.ErrorMatches, `x\[0\]: cannot generate an example for schema_test.unsatisfiableC`)
+}
+
+type unsatisfiableC struct{}
+
+func (unsatisfiableC) Coerce(v interface{}, path []string) (interface{}, error) {
+	return schema.Const("never").Coerce(v, path)
+}
@@ -5,7 +5,12 @@ package schema
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 // Omit is a marker for FieldMap and StructFieldMap defaults parameter.
@@ -18,6 +23,107 @@ type omit struct{}
 type Fields map[string]Checker
 type Defaults map[string]interface{}
 
+// Required returns a Checker that acts as checker, but marks the field
+// it is associated with in a FieldMap as required. If the field is not
+// present in the map, processing fails with a "missing required field"
+// error, rather than checker being called with a nil value.
+func Required(checker Checker) Checker {
+	return requiredC{checker}
+}
+
+type requiredC struct {
+	checker Checker
+}
+
+func (c requiredC) unwrap() Checker {
+	return c.checker
+}
+
+func (c requiredC) Coerce(v interface{}, path []string) (interface{}, error) {
+	return c.checker.Coerce(v, path)
+}
+
+// Optional returns a Checker that acts as checker, but marks the field
+// it is associated with in a FieldMap as optional. If the field is not
+// present in the map and has no default, it is omitted from the coerced
+// map, as if it defaulted to Omit.
+func Optional(checker Checker) Checker {
+	return optionalC{checker}
+}
+
+type optionalC struct {
+	checker Checker
+}
+
+func (c optionalC) unwrap() Checker {
+	return c.checker
+}
+
+func (c optionalC) Coerce(v interface{}, path []string) (interface{}, error) {
+	return c.checker.Coerce(v, path)
+}
+
+// Nullable returns a Checker that accepts nil, returning nil, and
+// otherwise acts as checker.
+//
+// When used for a field in a FieldMap, an explicit nil value is
+// distinguished from a missing key: defaults are not applied to it,
+// and it is either omitted from the coerced map or, if the
+// PreserveNulls option is set, kept as nil.
+func Nullable(checker Checker) Checker {
+	return nullableC{checker}
+}
+
+type nullableC struct {
+	checker Checker
+}
+
+func (c nullableC) unwrap() Checker {
+	return c.checker
+}
+
+func (c nullableC) Coerce(v interface{}, path []string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return c.checker.Coerce(v, path)
+}
+
+// Uncoerce implements Encoder.
+func (c nullableC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return uncoerce(c.checker, v, path)
+}
+
+// fieldFlags holds the markers applied to a field checker with
+// Required, Optional and Nullable.
+type fieldFlags struct {
+	required bool
+	optional bool
+	nullable bool
+}
+
+func flagsOf(checker Checker) fieldFlags {
+	var flags fieldFlags
+	for {
+		switch checker.(type) {
+		case requiredC:
+			flags.required = true
+		case optionalC:
+			flags.optional = true
+		case nullableC:
+			flags.nullable = true
+		}
+		w, ok := checker.(wrapper)
+		if !ok {
+			return flags
+		}
+		checker = w.unwrap()
+	}
+}
+
 // FieldMap returns a Checker that accepts a map value with defined
 // string keys. Every key has an independent checker associated,
 // and processing will only succeed if all the values succeed
@@ -26,23 +132,175 @@ type Defaults map[string]interface{}
 //
 // Fields in defaults will be set to the provided value if not present
 // in the coerced map. If the default value is schema.Omit, the
-// missing field will be omitted from the coerced map.
+// missing field will be omitted from the coerced map. Field checkers
+// may be wrapped with Required, Optional and Nullable to control how
+// missing keys and explicit nil values are handled.
 //
 // The coerced output value has type map[string]interface{}.
 func FieldMap(fields Fields, defaults Defaults) Checker {
-	return fieldMapC{fields, defaults, false}
+	return fieldMapC{fields: fields, defaults: defaults, secret: secretFields(fields)}
 }
 
 // StrictFieldMap returns a Checker that acts as the one returned by FieldMap,
 // but the Checker returns an error if it encounters an unknown key.
 func StrictFieldMap(fields Fields, defaults Defaults) Checker {
-	return fieldMapC{fields, defaults, true}
+	return fieldMapC{fields: fields, defaults: defaults, opts: FieldMapOptions{Strict: true}, secret: secretFields(fields)}
+}
+
+// FieldMapOptions holds optional behaviour for the Checker returned
+// by FieldMapWith.
+type FieldMapOptions struct {
+	// Strict causes unknown keys to be rejected, as done by
+	// StrictFieldMap.
+	Strict bool
+
+	// PreserveNulls causes explicit nil values of fields marked with
+	// Nullable to be kept in the coerced map. By default they are
+	// omitted from it.
+	PreserveNulls bool
+
+	// Constraints are checked in order against the coerced map,
+	// once all the fields have been processed successfully.
+	Constraints []Constraint
+
+	// Aliases maps alternative keys to the name of the field they
+	// stand for, which must exist. Processing fails if a field is
+	// specified with more than one of its names.
+	Aliases map[string]string
+
+	// Deprecated maps field names or aliases to a message explaining
+	// what to use instead, which may be empty. Deprecated keys are
+	// still accepted, but a warning is reported when they are used.
+	Deprecated map[string]string
+
+	// Renamed maps keys that are no longer accepted to the name of
+	// the field replacing them. When Strict is set, processing fails
+	// with an error pointing to the new name if one is used; otherwise
+	// the key is ignored and a warning is reported.
+	Renamed map[string]string
+
+	// Warn, if not nil, is called with each warning raised while
+	// processing a map, such as the use of a deprecated key.
+	Warn func(w Warning)
+
+	// NormalizeKey, if not nil, is used to match keys in the map
+	// against field names, aliases and renamed keys, which must all
+	// remain distinct once normalized. The declared name of a field
+	// is used in the coerced map and in error messages, and
+	// processing fails if several keys in the map normalize to the
+	// same one.
+	NormalizeKey KeyNormalizer
+
+	// Extra, if not nil, is used to process keys that are neither
+	// field names, aliases nor renamed keys. Such keys are kept in
+	// the coerced map with their coerced value, and are not rejected
+	// when Strict is set.
+	Extra Checker
+
+	// ExtraKeys, if not nil, restricts the keys processed by Extra
+	// to the ones it matches. Other unknown keys are treated as if
+	// Extra was not set.
+	ExtraKeys *regexp.Regexp
+
+	// KeepUnknown causes unknown keys not processed by Extra to be
+	// kept unchanged in the coerced map, rather than left out. Renamed
+	// keys are not kept. It has no effect when Strict is set.
+	KeepUnknown bool
+
+	// UnknownField, if not empty, causes unknown keys not processed
+	// by Extra to be collected unchanged into a map[string]interface{}
+	// stored under this key in the coerced map, rather than left out.
+	// The map is only added if there are unknown keys, and it takes
+	// precedence over KeepUnknown. It has no effect when Strict is set,
+	// and must not be the name of a field. Processing fails if the map
+	// holds a key named UnknownField.
+	UnknownField string
+}
+
+// FieldMapWith returns a Checker that acts as the one returned by
+// FieldMap, with additional behaviour configured by opts. It panics if
+// opts.UnknownField is the name of a field, if opts.Aliases holds an
+// alias for a field that does not exist, or if opts.NormalizeKey maps
+// two field names, aliases or renamed keys to the same key; see
+// NewFieldMapWith.
+func FieldMapWith(fields Fields, defaults Defaults, opts FieldMapOptions) Checker {
+	c, err := NewFieldMapWith(fields, defaults, opts)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewFieldMapWith returns a Checker that acts as the one returned by
+// FieldMapWith, or an error if opts.UnknownField is the name of a
+// field, if opts.Aliases holds an alias for a field that does not
+// exist, or if opts.NormalizeKey maps two field names, aliases or
+// renamed keys to the same key.
+func NewFieldMapWith(fields Fields, defaults Defaults, opts FieldMapOptions) (Checker, error) {
+	if _, ok := fields[opts.UnknownField]; ok && opts.UnknownField != "" {
+		return nil, fmt.Errorf("FieldMapWith got UnknownField %q that is the name of a field", opts.UnknownField)
+	}
+	aliases := make([]string, 0, len(opts.Aliases))
+	for alias := range opts.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if _, ok := fields[opts.Aliases[alias]]; !ok {
+			return nil, fmt.Errorf("FieldMapWith got alias %q for unknown field %q", alias, opts.Aliases[alias])
+		}
+	}
+	c := fieldMapC{
+		fields:   fields,
+		defaults: defaults,
+		opts:     opts,
+		secret:   secretFields(fields) || holdsSecret(opts.Extra),
+	}
+	if opts.NormalizeKey != nil {
+		c.keys = make(map[string]string)
+		var declared []string
+		for k := range fields {
+			declared = append(declared, k)
+		}
+		for k := range opts.Aliases {
+			declared = append(declared, k)
+		}
+		for k := range opts.Renamed {
+			declared = append(declared, k)
+		}
+		sort.Strings(declared)
+		for _, k := range declared {
+			nk := opts.NormalizeKey(k)
+			if other, ok := c.keys[nk]; ok {
+				return nil, fmt.Errorf("FieldMapWith got keys %q and %q that both normalize to %q", other, k, nk)
+			}
+			c.keys[nk] = k
+		}
+	}
+	return c, nil
 }
 
 type fieldMapC struct {
 	fields   Fields
 	defaults Defaults
-	strict   bool
+	opts     FieldMapOptions
+
+	// keys maps normalized keys to the declared key they match,
+	// when opts.NormalizeKey is set.
+	keys map[string]string
+
+	// secret holds whether any of the checkers is secret.
+	secret bool
+}
+
+// secretFields reports whether any of the checkers in fields is secret.
+func secretFields(fields Fields) bool {
+	for _, checker := range fields {
+		if holdsSecret(checker) {
+			return true
+		}
+	}
+	return false
 }
 
 var stringType = reflect.TypeOf("")
@@ -65,34 +323,43 @@ func hasStrictStringKeys(rv reflect.Value) bool {
 func (c fieldMapC) Coerce(v interface{}, path []string) (interface{}, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Map {
-		return nil, error_{"map", v, path}
+		return nil, error_{"map", errorValue(c, v), path}
 	}
 	if !hasStrictStringKeys(rv) {
-		return nil, error_{"map[string]", v, path}
+		return nil, error_{"map[string]", errorValue(c, v), path}
 	}
 
-	if c.strict {
-		for _, k := range rv.MapKeys() {
-			ks := k.String()
-			if _, ok := c.fields[ks]; !ok {
-				return nil, fmt.Errorf("%sunknown key %q (value %#v)", pathAsPrefix(path), ks, rv.MapIndex(k).Interface())
-			}
-		}
+	values, extras, unknown, err := c.inputs(rv, path)
+	if err != nil {
+		return nil, err
 	}
 
 	vpath := append(path, ".", "?")
 
 	out := make(map[string]interface{}, rv.Len())
 	for k, checker := range c.fields {
-		valuev := rv.MapIndex(reflect.ValueOf(k))
-		var value interface{}
-		if valuev.IsValid() {
-			value = valuev.Interface()
+		flags := flagsOf(checker)
+		value, present := values[k]
+		if present {
+			if value == nil && flags.nullable {
+				if c.opts.PreserveNulls {
+					out[k] = nil
+				}
+				continue
+			}
+		} else if flags.required {
+			msg := "missing required field"
+			if title := metaOf(checker).Title; title != "" {
+				msg += fmt.Sprintf(" (%s)", title)
+			}
+			return nil, fieldsError{path, []string{k}, msg}
 		} else if dflt, ok := c.defaults[k]; ok {
 			if dflt == Omit {
 				continue
 			}
 			value = dflt
+		} else if flags.optional {
+			continue
 		}
 		vpath[len(vpath)-1] = k
 		newv, err := checker.Coerce(value, vpath)
@@ -101,70 +368,910 @@ func (c fieldMapC) Coerce(v interface{}, path []string) (interface{}, error) {
 		}
 		out[k] = newv
 	}
+	extraKeys := make([]string, 0, len(extras))
+	for k := range extras {
+		extraKeys = append(extraKeys, k)
+	}
+	sort.Strings(extraKeys)
+	for _, k := range extraKeys {
+		vpath[len(vpath)-1] = k
+		newv, err := c.opts.Extra.Coerce(extras[k], vpath)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = newv
+	}
+	if len(unknown) > 0 {
+		if c.opts.UnknownField != "" {
+			out[c.opts.UnknownField] = unknown
+		} else {
+			for k, v := range unknown {
+				out[k] = v
+			}
+		}
+	}
 	for k, v := range c.defaults {
 		if v == Omit {
 			continue
 		}
-		if _, ok := out[k]; !ok {
-			checker, ok := c.fields[k]
+		if _, ok := c.fields[k]; !ok {
+			return nil, fmt.Errorf("got default value for unknown field %q", k)
+		}
+	}
+	for _, constraint := range c.opts.Constraints {
+		if err := constraint.Check(out, path); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Uncoerce implements Encoder. Fields set to their default value are
+// omitted, unless they are marked with Required, and unknown keys kept
+// in the coerced map are returned unchanged.
+func (c fieldMapC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, error_{"map[string]interface {}", errorValue(c, v), path}
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	vpath := append(path, ".", "?")
+
+	out := make(map[string]interface{}, len(m))
+	for _, k := range keys {
+		value := m[k]
+		vpath[len(vpath)-1] = k
+		checker, isField := c.fields[k]
+		switch {
+		case isField:
+			if c.isDefault(k, value) {
+				continue
+			}
+			newv, err := uncoerce(checker, value, vpath)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = newv
+		case c.opts.UnknownField != "" && k == c.opts.UnknownField:
+			unknown, ok := value.(map[string]interface{})
 			if !ok {
-				return nil, fmt.Errorf("got default value for unknown field %q", k)
+				return nil, error_{"map[string]interface {}", value, vpath}
 			}
-			vpath[len(vpath)-1] = k
-			newv, err := checker.Coerce(v, vpath)
+			for uk, uv := range unknown {
+				out[uk] = uv
+			}
+		case c.isExtra(k):
+			newv, err := uncoerce(c.opts.Extra, value, vpath)
 			if err != nil {
 				return nil, err
 			}
 			out[k] = newv
+		default:
+			out[k] = value
 		}
 	}
 	return out, nil
 }
 
+func (c fieldMapC) holdsSecret() bool {
+	return c.secret
+}
+
+func (c fieldMapC) redact(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, value := range m {
+		if checker, ok := c.fields[k]; ok {
+			out[k] = redact(checker, value)
+		} else if c.opts.Extra != nil && c.isExtra(k) {
+			out[k] = redact(c.opts.Extra, value)
+		} else {
+			out[k] = value
+		}
+	}
+	return out
+}
+
+// isDefault reports whether value is the coerced default value of the
+// named field, so that the field may be omitted when serialized.
+func (c fieldMapC) isDefault(name string, value interface{}) bool {
+	dflt, ok := c.defaults[name]
+	if !ok || dflt == Omit || flagsOf(c.fields[name]).required {
+		return false
+	}
+	coerced, err := c.fields[name].Coerce(dflt, nil)
+	return err == nil && reflect.DeepEqual(coerced, value)
+}
+
 // FieldMapSet returns a Checker that accepts a map value checked
 // against one of several FieldMap checkers.  The actual checker
 // used is the first one whose checker associated with the selector
 // field processes the map correctly. If no checker processes
-// the selector value correctly, an error is returned.
+// the selector value correctly, an error is returned, listing
+// the supported selector values when they are known.
+//
+// FieldMapSet panics if any of the checkers is not a FieldMap, or
+// has no selector field; see NewFieldMapSet.
 //
 // The coerced output value has type map[string]interface{}.
 func FieldMapSet(selector string, maps []Checker) Checker {
-	fmaps := make([]fieldMapC, len(maps))
+	return FieldMapSetWith(selector, maps, nil)
+}
+
+// NewFieldMapSet returns a Checker that acts as the one returned by
+// FieldMapSet, or an error if any of the checkers is not a FieldMap,
+// StrictFieldMap or FieldMapWith checker, possibly wrapped by checkers
+// such as Required, or if any of them has no selector field.
+func NewFieldMapSet(selector string, maps []Checker) (Checker, error) {
+	return NewFieldMapSetWith(selector, maps, nil)
+}
+
+// FieldMapSetWith returns a Checker that acts as the one returned by
+// FieldMapSet, except that a map with no selector field, or with a
+// selector value no checker accepts, is checked against fallback
+// instead, if it is not nil. The fallback checker may be any checker.
+//
+// FieldMapSetWith panics as FieldMapSet does; see NewFieldMapSetWith.
+func FieldMapSetWith(selector string, maps []Checker, fallback Checker) Checker {
+	c, err := NewFieldMapSetWith(selector, maps, fallback)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewFieldMapSetWith returns a Checker that acts as the one returned by
+// FieldMapSetWith, or an error as NewFieldMapSet does.
+func NewFieldMapSetWith(selector string, maps []Checker, fallback Checker) (Checker, error) {
+	branches := make([]mapSetBranch, len(maps))
 	for i, m := range maps {
-		if fmap, ok := m.(fieldMapC); ok {
-			if checker, _ := fmap.fields[selector]; checker == nil {
-				panic("FieldMapSet has a FieldMap with a missing selector")
-			}
-			fmaps[i] = fmap
-		} else {
-			panic("FieldMapSet got a non-FieldMap checker")
+		fmap, ok := asFieldMap(m)
+		if !ok {
+			return nil, fmt.Errorf("FieldMapSet got a non-FieldMap checker at index %d", i)
+		}
+		checker := fmap.fields[selector]
+		if checker == nil {
+			return nil, fmt.Errorf("FieldMapSet has a FieldMap with a missing selector %q at index %d", selector, i)
 		}
+		branches[i] = mapSetBranch{checker, m}
+	}
+	c := mapSetC{selector: selector, branches: branches, fallback: fallback}
+	c.secret = anySecret(c.checkers()...)
+	return c, nil
+}
+
+// asFieldMap returns the FieldMap checker underlying c, if any.
+func asFieldMap(c Checker) (fieldMapC, bool) {
+	for {
+		if fmap, ok := c.(fieldMapC); ok {
+			return fmap, true
+		}
+		w, ok := c.(wrapper)
+		if !ok {
+			return fieldMapC{}, false
+		}
+		c = w.unwrap()
 	}
-	return mapSetC{selector, fmaps}
 }
 
 type mapSetC struct {
 	selector string
-	fmaps    []fieldMapC
+	branches []mapSetBranch
+	fallback Checker
+
+	// secret holds whether any of the checkers is secret.
+	secret bool
+}
+
+type mapSetBranch struct {
+	// selector holds the checker for the selector field.
+	selector Checker
+
+	// checker holds the checker for the whole map.
+	checker Checker
 }
 
 func (c mapSetC) Coerce(v interface{}, path []string) (interface{}, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Map {
-		return nil, error_{"map", v, path}
+		return nil, error_{"map", errorValue(c, v), path}
 	}
 
-	var selector interface{}
-	selectorv := rv.MapIndex(reflect.ValueOf(c.selector))
-	if selectorv.IsValid() {
-		selector = selectorv.Interface()
-		for _, fmap := range c.fmaps {
-			_, err := fmap.fields[c.selector].Coerce(selector, path)
+	selector, ok := mapValue(rv, c.selector)
+	if ok {
+		for _, branch := range c.branches {
+			_, err := branch.selector.Coerce(selector, path)
 			if err != nil {
 				continue
 			}
-			return fmap.Coerce(v, path)
+			return branch.checker.Coerce(v, path)
+		}
+	}
+	if c.fallback != nil {
+		return c.fallback.Coerce(v, path)
+	}
+	return nil, error_{c.expected(), selector, append(path, ".", c.selector)}
+}
+
+// Uncoerce implements Encoder. The value is converted by the checker
+// whose selector checker accepts the coerced selector value.
+func (c mapSetC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, error_{"map[string]interface {}", errorValue(c, v), path}
+	}
+	selector := m[c.selector]
+	for _, branch := range c.branches {
+		if _, err := branch.selector.Coerce(selector, path); err == nil {
+			return uncoerce(branch.checker, v, path)
+		}
+	}
+	if c.fallback != nil {
+		return uncoerce(c.fallback, v, path)
+	}
+	return nil, error_{c.expected(), selector, append(path, ".", c.selector)}
+}
+
+func (c mapSetC) holdsSecret() bool {
+	return c.secret
+}
+
+func (c mapSetC) redact(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for _, branch := range c.branches {
+		if _, err := branch.selector.Coerce(m[c.selector], nil); err == nil {
+			return redact(branch.checker, v)
+		}
+	}
+	if c.fallback != nil {
+		return redact(c.fallback, v)
+	}
+	return v
+}
+
+// expected returns the label describing the supported selector
+// values, which are listed if they are all constants.
+func (c mapSetC) expected() string {
+	values := make([]string, len(c.branches))
+	for i, branch := range c.branches {
+		checker := branch.selector
+		for {
+			w, ok := checker.(wrapper)
+			if !ok {
+				break
+			}
+			checker = w.unwrap()
+		}
+		cc, ok := checker.(constC)
+		if !ok {
+			return "supported selector"
+		}
+		values[i] = fmt.Sprintf("%#v", cc.value)
+	}
+	return fmt.Sprintf("supported selector (%s)", strings.Join(values, ", "))
+}
+
+// FieldMapSwitch returns a Checker that accepts a map value checked
+// against the checker in branches keyed by the value of the selector
+// field. The selector value is looked up as found in the map, without
+// being coerced, except that numbers are matched by value, so that a
+// branch keyed by 1 is chosen for int64(1) or float64(1) as decoded
+// from JSON. If there is no selector field, or its value has no entry
+// in branches, the map is checked against fallback instead, or an
+// error listing the supported selector values is returned if fallback
+// is nil.
+//
+// Keys of branches must be distinct once numbers are matched by value;
+// when several are equal, the one sorted first is used.
+//
+// The coerced output value is the one produced by the selected checker.
+func FieldMapSwitch(selector string, branches map[interface{}]Checker, fallback Checker) Checker {
+	c := mapSwitchC{
+		selector: selector,
+		branches: branches,
+		fallback: fallback,
+		keys:     make(map[interface{}]Checker, len(branches)),
+	}
+	for _, value := range c.values() {
+		key := selectorKey(value)
+		if _, ok := c.keys[key]; !ok {
+			c.keys[key] = branches[value]
 		}
 	}
-	return nil, error_{"supported selector", selector, append(path, ".", c.selector)}
+	c.secret = anySecret(c.checkers()...)
+	return c
+}
+
+type mapSwitchC struct {
+	selector string
+	branches map[interface{}]Checker
+	fallback Checker
+
+	// keys holds the checkers in branches keyed by selectorKey.
+	keys map[interface{}]Checker
+
+	// secret holds whether any of the checkers is secret.
+	secret bool
+}
+
+// values returns the selector values of the branches, sorted.
+func (c mapSwitchC) values() []interface{} {
+	values := make([]interface{}, 0, len(c.branches))
+	for value := range c.branches {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return lessValue(values[i], values[j])
+	})
+	return values
+}
+
+// lookup returns the selector value in the map held by rv, and the
+// checker of the branch it selects, if any.
+func (c mapSwitchC) lookup(rv reflect.Value) (interface{}, Checker) {
+	selector, ok := mapValue(rv, c.selector)
+	if !ok || selector == nil || !reflect.TypeOf(selector).Comparable() {
+		return selector, nil
+	}
+	return selector, c.keys[selectorKey(selector)]
+}
+
+// selectorKey returns the key used to look up the branch selected by
+// v. Integral numbers are converted to int64 and other numbers to
+// float64, so that numbers of different types are matched by value.
+func selectorKey(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f)
+		}
+		return f
+	}
+	return v
+}
+
+// mapValue returns the value of the given key in the map held by rv,
+// and whether it was found. Maps whose keys can't be strings never
+// hold the key.
+func mapValue(rv reflect.Value, key string) (interface{}, bool) {
+	kv := reflect.ValueOf(key)
+	kt := rv.Type().Key()
+	switch {
+	case kt.Kind() == reflect.String:
+		kv = kv.Convert(kt)
+	case !kv.Type().AssignableTo(kt):
+		return nil, false
+	}
+	v := rv.MapIndex(kv)
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+func (c mapSwitchC) Coerce(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, error_{"map", errorValue(c, v), path}
+	}
+
+	selector, checker := c.lookup(rv)
+	if checker != nil {
+		return checker.Coerce(v, path)
+	}
+	if c.fallback != nil {
+		return c.fallback.Coerce(v, path)
+	}
+	values := make([]string, 0, len(c.branches))
+	for value := range c.branches {
+		values = append(values, fmt.Sprintf("%#v", value))
+	}
+	sort.Strings(values)
+	want := fmt.Sprintf("one of %s", strings.Join(values, ", "))
+	return nil, error_{want, selector, append(path, ".", c.selector)}
+}
+
+// Uncoerce implements Encoder.
+func (c mapSwitchC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, error_{"map", errorValue(c, v), path}
+	}
+	if checker := c.branch(rv); checker != nil {
+		return uncoerce(checker, v, path)
+	}
+	return v, nil
+}
+
+func (c mapSwitchC) holdsSecret() bool {
+	return c.secret
+}
+
+func (c mapSwitchC) redact(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return v
+	}
+	if checker := c.branch(rv); checker != nil {
+		return redact(checker, v)
+	}
+	return v
+}
+
+// branch returns the checker of the branch chosen by the selector
+// value in the coerced map held by rv, or the fallback checker.
+func (c mapSwitchC) branch(rv reflect.Value) Checker {
+	if _, checker := c.lookup(rv); checker != nil {
+		return checker
+	}
+	return c.fallback
+}
+
+// inputs returns the values in the map held by rv, keyed by the name of
+// the field they are for, and separately the values to be processed by
+// the Extra checker and the ones to be kept unchanged. Other unknown keys
+// are either left out or, in strict mode, result in an error.
+func (c fieldMapC) inputs(rv reflect.Value, path []string) (values, extras, unknown map[string]interface{}, err error) {
+	values = make(map[string]interface{}, rv.Len())
+	extras = make(map[string]interface{})
+	unknown = make(map[string]interface{})
+	sources := make(map[string]string, rv.Len())
+	// Keys are sorted so that warnings and errors are reported in a
+	// consistent order.
+	for _, k := range sortedKeys(rv) {
+		given := keyString(k)
+		ks := c.declaredKey(given)
+		name := ks
+		if _, ok := c.fields[ks]; !ok {
+			if field, ok := c.opts.Aliases[ks]; ok {
+				name = field
+			} else if field, ok := c.opts.Renamed[ks]; ok {
+				if c.opts.Strict {
+					return nil, nil, nil, fmt.Errorf("%sfield %q was renamed to %q", pathAsPrefix(path), given, field)
+				}
+				c.warn(path, given, fmt.Sprintf("field was renamed to %q", field))
+				continue
+			} else if c.opts.UnknownField != "" && !c.opts.Strict && ks == c.opts.UnknownField {
+				return nil, nil, nil, fieldsError{path, []string{given}, "key is reserved for unknown keys"}
+			} else if c.isExtra(given) {
+				extras[given] = rv.MapIndex(k).Interface()
+				continue
+			} else if !c.opts.Strict {
+				if c.keepUnknown() {
+					unknown[given] = rv.MapIndex(k).Interface()
+				}
+				continue
+			} else {
+				// The key may be a mistyped secret field, so its
+				// value is not reported if any field is secret.
+				var value interface{} = redacted{}
+				if !c.secret {
+					value = rv.MapIndex(k).Interface()
+				}
+				return nil, nil, nil, &UnknownKeyError{
+					Path:        path,
+					Key:         given,
+					Value:       value,
+					Suggestions: c.suggest(given),
+				}
+			}
+		}
+		if other, ok := sources[name]; ok {
+			keys := []string{other, given}
+			sort.Strings(keys)
+			if c.declaredKey(other) == ks {
+				return nil, nil, nil, fieldsError{path, keys, "duplicate keys"}
+			}
+			return nil, nil, nil, fieldsError{path, keys, "only one may be specified"}
+		}
+		sources[name] = given
+		values[name] = rv.MapIndex(k).Interface()
+		msg, deprecated := c.opts.Deprecated[ks]
+		if !deprecated {
+			meta := metaOf(c.fields[name])
+			msg, deprecated = meta.DeprecationMessage, meta.Deprecated
+		}
+		if deprecated {
+			if msg != "" {
+				msg = ": " + msg
+			}
+			c.warn(path, ks, "field is deprecated"+msg)
+		}
+	}
+	return values, extras, unknown, nil
+}
+
+// keepUnknown reports whether unknown keys are to be preserved.
+func (c fieldMapC) keepUnknown() bool {
+	return c.opts.KeepUnknown || c.opts.UnknownField != ""
+}
+
+// isExtra reports whether the given unknown key should be processed by
+// the Extra checker.
+func (c fieldMapC) isExtra(key string) bool {
+	if c.opts.Extra == nil {
+		return false
+	}
+	return c.opts.ExtraKeys == nil || c.opts.ExtraKeys.MatchString(key)
+}
+
+// suggest returns the field names and aliases closest to the given
+// unknown key.
+func (c fieldMapC) suggest(key string) []string {
+	normalize := c.opts.NormalizeKey
+	if normalize == nil {
+		normalize = func(k string) string { return k }
+	}
+	var names []string
+	byNormal := make(map[string]string)
+	for k := range c.fields {
+		names = append(names, normalize(k))
+		byNormal[normalize(k)] = k
+	}
+	for k := range c.opts.Aliases {
+		names = append(names, normalize(k))
+		byNormal[normalize(k)] = k
+	}
+	suggestions := suggestKeys(normalize(key), names)
+	for i, s := range suggestions {
+		suggestions[i] = byNormal[s]
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// declaredKey returns the field name, alias or renamed key matching
+// the given key once normalized, or the key itself if there is none.
+func (c fieldMapC) declaredKey(key string) string {
+	if c.opts.NormalizeKey == nil {
+		return key
+	}
+	if k, ok := c.keys[c.opts.NormalizeKey(key)]; ok {
+		return k
+	}
+	return key
+}
+
+// warn reports a warning about the given key of the map at path.
+func (c fieldMapC) warn(path []string, key, message string) {
+	if c.opts.Warn == nil {
+		return
+	}
+	wpath := make([]string, 0, len(path)+2)
+	wpath = append(wpath, path...)
+	wpath = append(wpath, ".", key)
+	c.opts.Warn(Warning{wpath, message})
+}
+
+// keyString returns the string held by k, which must be a string or an
+// interface holding a string.
+func keyString(k reflect.Value) string {
+	if k.Kind() == reflect.Interface {
+		k = k.Elem()
+	}
+	return k.String()
+}
+
+// Describe implements Descriptor.
+func (c nullableC) Describe() Description {
+	d := Describe(c.checker)
+	d.Nullable = true
+	return d
+}
+
+// Describe implements Descriptor.
+func (c fieldMapC) Describe() Description {
+	d := Description{Kind: KindFieldMap, Label: "map"}
+	aliases := make(map[string][]string)
+	for alias, name := range c.opts.Aliases {
+		aliases[name] = append(aliases[name], alias)
+	}
+	for _, name := range c.names() {
+		checker := c.fields[name]
+		flags := flagsOf(checker)
+		f := FieldDescription{
+			Name:    name,
+			Checker: Describe(checker),
+			Aliases: aliases[name],
+		}
+		sort.Strings(f.Aliases)
+		dflt, hasDefault := c.defaults[name]
+		if hasDefault && dflt != Omit {
+			f.HasDefault = true
+			f.Default = dflt
+		}
+		f.Required = flags.required || !hasDefault && !flags.optional && !acceptsNil(f.Checker)
+		f.DeprecationMessage, f.Deprecated = c.opts.Deprecated[name]
+		if !f.Deprecated && f.Checker.Meta.Deprecated {
+			f.Deprecated = true
+			f.DeprecationMessage = f.Checker.Meta.DeprecationMessage
+		}
+		d.Fields = append(d.Fields, f)
+	}
+	if c.opts.Strict {
+		d.Constraints = append(d.Constraints, "no unknown keys")
+	} else if c.keepUnknown() {
+		d.Constraints = append(d.Constraints, "unknown keys kept")
+	}
+	if c.opts.Extra != nil {
+		d.Elem = describePtr(c.opts.Extra)
+		if c.opts.ExtraKeys != nil {
+			d.Constraints = append(d.Constraints, fmt.Sprintf("additional keys matching %q", c.opts.ExtraKeys))
+		}
+	}
+	for _, constraint := range c.opts.Constraints {
+		d.Constraints = append(d.Constraints, describeConstraint(constraint))
+	}
+	return d
+}
+
+// Describe implements Descriptor.
+func (c mapSetC) Describe() Description {
+	d := Description{
+		Kind:     KindFieldMapSet,
+		Label:    fmt.Sprintf("map selected by %s", c.selector),
+		Selector: c.selector,
+	}
+	for _, branch := range c.branches {
+		b := Branch{Checker: Describe(branch.checker)}
+		if selector := Describe(branch.selector); selector.Kind == KindConst {
+			b.Value, b.HasValue = selector.Value, true
+		}
+		d.Branches = append(d.Branches, b)
+	}
+	if c.fallback != nil {
+		d.Branches = append(d.Branches, Branch{Checker: Describe(c.fallback)})
+	}
+	return d
+}
+
+// Describe implements Descriptor.
+func (c mapSwitchC) Describe() Description {
+	d := Description{
+		Kind:     KindFieldMapSwitch,
+		Label:    fmt.Sprintf("map selected by %s", c.selector),
+		Selector: c.selector,
+	}
+	for _, value := range c.values() {
+		d.Branches = append(d.Branches, Branch{
+			Value:    value,
+			HasValue: true,
+			Checker:  Describe(c.branches[value]),
+		})
+	}
+	if c.fallback != nil {
+		d.Branches = append(d.Branches, Branch{Checker: Describe(c.fallback)})
+	}
+	return d
+}
+
+func (c fieldMapC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	out := make(map[string]interface{}, len(c.fields))
+	vpath := append(path, ".", "?")
+	add := func(name string) error {
+		checker, ok := c.fields[name]
+		if !ok {
+			return nil
+		}
+		vpath[len(vpath)-1] = name
+		v, err := example(checker, opts, vpath)
+		if err != nil {
+			return err
+		}
+		out[name] = v
+		return nil
+	}
+	for _, name := range c.names() {
+		checker := c.fields[name]
+		flags := flagsOf(checker)
+		if flags.required {
+			if err := add(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if dflt, ok := c.defaults[name]; ok && dflt != Omit {
+			vpath[len(vpath)-1] = name
+			v, err := exampleDefault(checker, dflt, vpath)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = v
+			continue
+		}
+		_, nilErr := checker.Coerce(nil, nil)
+		_, deprecated := c.opts.Deprecated[name]
+		deprecated = deprecated || metaOf(checker).Deprecated
+		_, hasDefault := c.defaults[name]
+		optional := hasDefault || flags.optional || nilErr == nil
+		if !optional || opts.AllFields && !deprecated {
+			if err := add(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, constraint := range c.opts.Constraints {
+		if err := satisfy(constraint, out, add); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// exampleDefault returns the default value dflt of a field processed
+// by checker as plain data. Defaults may be given in a form that is
+// not, such as a time.Duration, so they are converted with the Encoder
+// of checker when it has one.
+func exampleDefault(checker Checker, dflt interface{}, path []string) (interface{}, error) {
+	if !hasEncoder(checker) {
+		return dflt, nil
+	}
+	v, err := checker.Coerce(dflt, path)
+	if err != nil {
+		return nil, err
+	}
+	return uncoerce(checker, v, path)
+}
+
+func (c mapSetC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	checkers := c.checkers()
+	if len(checkers) == 0 {
+		return nil, fmt.Errorf("%scannot generate an example for FieldMapSet with no checkers", pathAsPrefix(path))
+	}
+	return example(checkers[0], opts, path)
+}
+
+func (c mapSwitchC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	checkers := c.checkers()
+	if len(checkers) == 0 {
+		return nil, fmt.Errorf("%scannot generate an example for FieldMapSwitch with no branches", pathAsPrefix(path))
+	}
+	return example(checkers[0], opts, path)
+}
+
+func (c fieldMapC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	out := make(map[string]interface{}, len(c.fields))
+	add := func(name string) error {
+		checker, ok := c.fields[name]
+		if !ok {
+			return nil
+		}
+		v, err := generate(checker, r, childPath(path, ".", name))
+		if err != nil {
+			return err
+		}
+		out[name] = v
+		return nil
+	}
+	for _, name := range c.names() {
+		checker := c.fields[name]
+		if !flagsOf(checker).required {
+			_, deprecated := c.opts.Deprecated[name]
+			if deprecated || metaOf(checker).Deprecated {
+				continue
+			}
+			_, hasDefault := c.defaults[name]
+			_, nilErr := checker.Coerce(nil, nil)
+			optional := hasDefault || flagsOf(checker).optional || nilErr == nil
+			if optional && r.Intn(2) == 0 {
+				continue
+			}
+		}
+		if err := add(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, constraint := range c.opts.Constraints {
+		if err := satisfy(constraint, out, add); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (c fieldMapC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	v, err := c.generate(r, path)
+	if err != nil {
+		return invalidLeaf(c, r, path)
+	}
+	out := v.(map[string]interface{})
+	names := c.names()
+	for _, i := range r.Perm(len(names)) {
+		name := names[i]
+		fv, fpath, ok := generateInvalid(c.fields[name], r, childPath(path, ".", name))
+		if ok && fv != nil {
+			out[name] = fv
+			return out, fpath, true
+		}
+	}
+	return invalidLeaf(c, r, path)
+}
+
+// names returns the names of the fields, sorted.
+func (c fieldMapC) names() []string {
+	names := make([]string, 0, len(c.fields))
+	for name := range c.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkers returns the checkers of the branches, followed by the
+// fallback checker if any.
+func (c mapSetC) checkers() []Checker {
+	checkers := make([]Checker, 0, len(c.branches)+1)
+	for _, branch := range c.branches {
+		checkers = append(checkers, branch.checker)
+	}
+	if c.fallback != nil {
+		checkers = append(checkers, c.fallback)
+	}
+	return checkers
+}
+
+func (c mapSetC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	checkers := c.checkers()
+	if len(checkers) == 0 {
+		return nil, fmt.Errorf("%scannot generate a value for FieldMapSet with no checkers", pathAsPrefix(path))
+	}
+	return generate(checkers[r.Intn(len(checkers))], r, path)
+}
+
+func (c mapSetC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	checkers := c.checkers()
+	if len(checkers) == 0 {
+		return invalidLeaf(c, r, path)
+	}
+	return generateInvalid(checkers[r.Intn(len(checkers))], r, path)
+}
+
+// checkers returns the checkers of the branches, ordered by selector
+// value, followed by the fallback checker if any.
+func (c mapSwitchC) checkers() []Checker {
+	values := c.values()
+	checkers := make([]Checker, 0, len(values)+1)
+	for _, value := range values {
+		checkers = append(checkers, c.branches[value])
+	}
+	if c.fallback != nil {
+		checkers = append(checkers, c.fallback)
+	}
+	return checkers
+}
+
+func (c mapSwitchC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	checkers := c.checkers()
+	if len(checkers) == 0 {
+		return nil, fmt.Errorf("%scannot generate a value for FieldMapSwitch with no branches", pathAsPrefix(path))
+	}
+	return generate(checkers[r.Intn(len(checkers))], r, path)
+}
+
+func (c mapSwitchC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	checkers := c.checkers()
+	if len(checkers) == 0 {
+		return invalidLeaf(c, r, path)
+	}
+	return generateInvalid(checkers[r.Intn(len(checkers))], r, path)
 }
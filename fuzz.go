@@ -0,0 +1,73 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// CheckCoerce coerces v with c, and returns an error if c panics or
+// violates one of the invariants expected from checkers: a coerced
+// value converted back to plain data with Uncoerce must be accepted by
+// c, and coercing and uncoercing it again must return the same plain
+// data, so that round-tripping configuration is stable. Errors
+// returned by c when coercing v itself are not reported, as rejecting
+// invalid input is expected.
+//
+// CheckCoerce is intended for fuzz tests of custom checkers; see
+// FuzzCoerce.
+func CheckCoerce(c Checker, v interface{}) (err error) {
+	step := "coercing"
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while %s %#v: %v", step, v, r)
+		}
+	}()
+	out, err := c.Coerce(v, nil)
+	if err != nil {
+		return nil
+	}
+	step = "uncoercing the result of"
+	plain, err := Uncoerce(c, out)
+	if err != nil {
+		return fmt.Errorf("cannot uncoerce %#v coerced from %#v: %v", out, v, err)
+	}
+	step = "coercing again the result of"
+	again, err := c.Coerce(plain, nil)
+	if err != nil {
+		return fmt.Errorf("cannot coerce %#v uncoerced from %#v: %v", plain, v, err)
+	}
+	step = "uncoercing again the result of"
+	plainAgain, err := Uncoerce(c, again)
+	if err != nil {
+		return fmt.Errorf("cannot uncoerce %#v coerced from %#v: %v", again, plain, err)
+	}
+	if !reflect.DeepEqual(plainAgain, plain) {
+		return fmt.Errorf("%#v coerced from %#v uncoerces to %#v, but then to %#v", out, v, plain, plainAgain)
+	}
+	return nil
+}
+
+// FuzzCoerce runs CheckCoerce with c and a value decoded from data,
+// which is typically provided by a fuzzing engine. The value is
+// decoded from data as JSON, and is the string held by data when it is
+// not valid JSON. For instance:
+//
+//	func FuzzConfig(f *testing.F) {
+//		f.Add([]byte(`{"name": "app"}`))
+//		f.Fuzz(func(t *testing.T, data []byte) {
+//			if err := schema.FuzzCoerce(configSchema, data); err != nil {
+//				t.Fatal(err)
+//			}
+//		})
+//	}
+func FuzzCoerce(c Checker, data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		v = string(data)
+	}
+	return CheckCoerce(c, v)
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build go1.18
+// +build go1.18
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/juju/schema"
+)
+
+func fuzzChecker(f *testing.F, c schema.Checker, seeds ...string) {
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := schema.FuzzCoerce(c, data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzSize(f *testing.F) {
+	fuzzChecker(f, schema.Size(), `"1"`, `"1.5G"`, `"10MiB"`, `"0.1T"`, `"3x"`, `""`)
+}
+
+func FuzzForceInt(f *testing.F) {
+	fuzzChecker(f, schema.ForceInt(), `"12"`, `"1.5"`, `12.7`, `-3`, `"1e3"`)
+}
+
+func FuzzForceUint(f *testing.F) {
+	fuzzChecker(f, schema.ForceUint(), `"12"`, `"1.5"`, `12.7`, `-3`)
+}
+
+func FuzzInt(f *testing.F) {
+	fuzzChecker(f, schema.Int(), `"12"`, `"-3"`, `"0x10"`)
+}
+
+func FuzzUint(f *testing.F) {
+	fuzzChecker(f, schema.Uint(), `"12"`, `"-3"`)
+}
+
+func FuzzFloat(f *testing.F) {
+	fuzzChecker(f, schema.Float(), `"1.5"`, `1e300`, `"NaN"`)
+}
+
+func FuzzBool(f *testing.F) {
+	fuzzChecker(f, schema.Bool(), `"true"`, `"F"`, `true`)
+}
+
+func FuzzStringified(f *testing.F) {
+	fuzzChecker(f, schema.Stringified(), `"x"`, `1.5`, `true`, `null`, `[1]`)
+}
+
+func FuzzURL(f *testing.F) {
+	fuzzChecker(f, schema.URL(), `"https://example.com/a?b=c#d"`, `"%zz"`, `"//x"`)
+}
+
+func FuzzSimpleRegexp(f *testing.F) {
+	fuzzChecker(f, schema.SimpleRegexp(), `"^a+[0-9]*$"`, `"(?i)x|y"`, `"["`, `""`)
+}
+
+func FuzzNonEmptyString(f *testing.F) {
+	fuzzChecker(f, schema.NonEmptyString("name"), `"x"`, `""`, `" "`, `1`)
+}
+
+func FuzzTime(f *testing.F) {
+	fuzzChecker(f, schema.Time(), `"2006-01-02T15:04:05Z"`, `"2006-01-02T15:04:05.999+07:00"`, `""`)
+}
+
+func FuzzTimeDuration(f *testing.F) {
+	fuzzChecker(f, schema.TimeDuration(), `"1h2m"`, `"-1.5s"`, `""`)
+}
+
+func FuzzUUID(f *testing.F) {
+	fuzzChecker(f, schema.UUID(), `"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`)
+}
+
+func FuzzList(f *testing.F) {
+	fuzzChecker(f, schema.ListWith(schema.Int(), schema.ListOptions{
+		Separator: ",",
+		Single:    true,
+		Unique:    true,
+	}), `"1,2,3"`, `[1, "2"]`, `"7"`)
+}
+
+func FuzzFieldMap(f *testing.F) {
+	fuzzChecker(f, schema.FieldMapWith(schema.Fields{
+		"name":  schema.String(),
+		"size":  schema.Size(),
+		"units": schema.List(schema.Int()),
+		"tags":  schema.StringMap(schema.Stringified()),
+	}, schema.Defaults{
+		"size":  "1G",
+		"units": schema.Omit,
+		"tags":  schema.Omit,
+	}, schema.FieldMapOptions{
+		Aliases:      map[string]string{"n": "name"},
+		NormalizeKey: schema.FoldKeyCase,
+	}), `{"name": "app"}`, `{"N": "app", "size": "2M", "tags": {"a": 1}}`)
+}
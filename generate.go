@@ -0,0 +1,240 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// generateAttempts holds the number of values generated by Generate and
+// GenerateInvalid before giving up.
+const generateAttempts = 20
+
+// Generate returns a random value accepted by c, made of plain data
+// that can be serialized, using src as the source of randomness. The
+// same value is returned for the same checker and seed, so that
+// failing property tests can be reproduced.
+//
+// Values are generated from what Describe would report about each
+// checker. Checkers restricting values in other ways, such as custom
+// checkers or Predicate constraints, may cause generated values to be
+// rejected; Generate then tries again, and
+// returns an error if no valid value was found after several attempts.
+func Generate(c Checker, src rand.Source) (interface{}, error) {
+	r := rand.New(src)
+	var err error
+	for i := 0; i < generateAttempts; i++ {
+		var v interface{}
+		v, err = generate(c, r, nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = c.Coerce(v, nil); err == nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot generate a valid value: %v", err)
+}
+
+// GenerateInvalid returns a random value rejected by c, using src as
+// the source of randomness. The value is valid except at a single
+// location, which is returned as a path formatted as in error messages,
+// such as "units[2].name", or "" when the whole value is invalid.
+// Generated values that c rejects with an error about another location,
+// as custom checkers may do, are discarded.
+func GenerateInvalid(c Checker, src rand.Source) (v interface{}, path string, err error) {
+	r := rand.New(src)
+	for i := 0; i < generateAttempts; i++ {
+		v, vpath, ok := generateInvalid(c, r, nil)
+		if !ok {
+			return nil, "", fmt.Errorf("cannot generate an invalid value for %s", Describe(c).Label)
+		}
+		prefix := pathAsPrefix(vpath)
+		if _, err := c.Coerce(v, nil); err != nil && strings.HasPrefix(err.Error(), prefix) {
+			return v, strings.TrimSuffix(prefix, ": "), nil
+		}
+	}
+	return nil, "", fmt.Errorf("cannot generate an invalid value for %s", Describe(c).Label)
+}
+
+// generator is implemented by checkers that can generate random values
+// they accept.
+type generator interface {
+	generate(r *rand.Rand, path []string) (interface{}, error)
+}
+
+// invalidGenerator is implemented by checkers holding other checkers,
+// that can generate a value rejected by one of them. It returns the
+// path to the invalid value, and false if no invalid value can be
+// generated.
+type invalidGenerator interface {
+	generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool)
+}
+
+func generate(c Checker, r *rand.Rand, path []string) (interface{}, error) {
+	if g, ok := c.(generator); ok {
+		return g.generate(r, path)
+	}
+	if w, ok := c.(wrapper); ok {
+		return generate(w.unwrap(), r, path)
+	}
+	var valid []interface{}
+	for _, v := range exampleCandidates {
+		if _, err := c.Coerce(v, path); err == nil {
+			valid = append(valid, v)
+		}
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("%scannot generate a value for %T", pathAsPrefix(path), c)
+	}
+	return valid[r.Intn(len(valid))], nil
+}
+
+// invalidCandidates holds the values tried for checkers that don't
+// implement invalidGenerator.
+var invalidCandidates = []interface{}{
+	nil,
+	complex(1, 1),
+	-1,
+	true,
+	"\x00invalid",
+	[]interface{}{},
+	map[string]interface{}{},
+}
+
+func generateInvalid(c Checker, r *rand.Rand, path []string) (interface{}, []string, bool) {
+	for inner := c; ; {
+		if g, ok := inner.(invalidGenerator); ok {
+			return g.generateInvalid(r, path)
+		}
+		w, ok := inner.(wrapper)
+		if !ok {
+			break
+		}
+		inner = w.unwrap()
+	}
+	// Leaf values are checked against c itself, so that for instance
+	// nil is not chosen for a Nullable checker.
+	return invalidLeaf(c, r, path)
+}
+
+// invalidLeaf returns a randomly chosen value rejected by c as a
+// whole.
+func invalidLeaf(c Checker, r *rand.Rand, path []string) (interface{}, []string, bool) {
+	for _, i := range r.Perm(len(invalidCandidates)) {
+		v := invalidCandidates[i]
+		if _, err := c.Coerce(v, path); err != nil {
+			return v, path, true
+		}
+	}
+	return nil, nil, false
+}
+
+func (c metaC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	if len(c.meta.Examples) > 0 && r.Intn(2) == 0 {
+		return c.meta.Examples[r.Intn(len(c.meta.Examples))], nil
+	}
+	return generate(c.checker, r, path)
+}
+
+const generateLetters = "abcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// randomString returns a random string of length between min and max.
+func randomString(r *rand.Rand, min, max int) string {
+	b := make([]byte, min+r.Intn(max-min+1))
+	for i := range b {
+		b[i] = generateLetters[r.Intn(len(generateLetters))]
+	}
+	return string(b)
+}
+
+// randomWord returns a random non-empty string made of lower case
+// letters.
+func randomWord(r *rand.Rand) string {
+	b := make([]byte, 1+r.Intn(8))
+	for i := range b {
+		b[i] = byte('a' + r.Intn(26))
+	}
+	return string(b)
+}
+
+// randomMatch returns a random string matched by pattern, or false if
+// none was found.
+func randomMatch(r *rand.Rand, pattern *regexp.Regexp) (string, bool) {
+	re, err := syntax.Parse(pattern.String(), syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+	for i := 0; i < generateAttempts; i++ {
+		var buf strings.Builder
+		writeMatch(&buf, r, re)
+		if s := buf.String(); pattern.MatchString(s) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// writeMatch writes to buf a random string matched by re. Empty-width
+// assertions such as anchors and word boundaries are ignored, so the
+// result must be checked against the pattern.
+func writeMatch(buf *strings.Builder, r *rand.Rand, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, c := range re.Rune {
+			buf.WriteRune(c)
+		}
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return
+		}
+		// Rune holds pairs of inclusive bounds. Only the start of
+		// large ranges is used, to keep generated strings readable.
+		i := 2 * r.Intn(len(re.Rune)/2)
+		lo, hi := re.Rune[i], re.Rune[i+1]
+		if hi-lo > 25 {
+			hi = lo + 25
+		}
+		buf.WriteRune(lo + rune(r.Intn(int(hi-lo)+1)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		buf.WriteByte(generateLetters[r.Intn(len(generateLetters))])
+	case syntax.OpCapture:
+		writeMatch(buf, r, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeMatch(buf, r, sub)
+		}
+	case syntax.OpAlternate:
+		writeMatch(buf, r, re.Sub[r.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, 3
+		case syntax.OpPlus:
+			min, max = 1, 4
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max < min {
+			max = min + 3
+		}
+		for n := min + r.Intn(max-min+1); n > 0; n-- {
+			writeMatch(buf, r, re.Sub[0])
+		}
+	}
+}
+
+// childPath returns a copy of path with the given elements appended,
+// which can be safely retained.
+func childPath(path []string, elems ...string) []string {
+	cpath := make([]string, 0, len(path)+len(elems))
+	cpath = append(cpath, path...)
+	return append(cpath, elems...)
+}
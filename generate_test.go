@@ -0,0 +1,176 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+var generateSchemas = []schema.Checker{
+	schema.Any(),
+	schema.Bool(),
+	schema.Int(),
+	schema.Uint(),
+	schema.ForceInt(),
+	schema.ForceUint(),
+	schema.Float(),
+	schema.String(),
+	schema.NonEmptyString("name"),
+	schema.URL(),
+	schema.SimpleRegexp(),
+	schema.UUID(),
+	schema.Stringified(),
+	schema.Size(),
+	schema.Time(),
+	schema.TimeDuration(),
+	schema.Const("x"),
+	schema.OneOf(schema.Int(), schema.String()),
+	schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 1, MaxLen: 2}),
+	schema.Tuple(schema.String(), schema.Int()),
+	schema.Map(schema.String(), schema.Int()),
+	schema.StringMap(schema.Bool()),
+	schema.Nullable(schema.Int()),
+	schema.FieldMapWith(schema.Fields{
+		"name":  schema.String(),
+		"port":  schema.Int(),
+		"units": schema.List(schema.FieldMap(schema.Fields{"id": schema.Int()}, nil)),
+		"file":  schema.Optional(schema.String()),
+		"url":   schema.Optional(schema.URL()),
+	}, schema.Defaults{
+		"port":  80,
+		"units": schema.Omit,
+	}, schema.FieldMapOptions{
+		Constraints: []schema.Constraint{schema.ExactlyOneOf("file", "url")},
+	}),
+	schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"a": schema.FieldMap(schema.Fields{"type": schema.Const("a"), "a": schema.Int()}, nil),
+		"b": schema.FieldMap(schema.Fields{"type": schema.Const("b"), "b": schema.String()}, nil),
+	}, nil),
+}
+
+func (s *S) TestGenerate(c *gc.C) {
+	for i, checker := range generateSchemas {
+		for seed := int64(0); seed < 20; seed++ {
+			v, err := schema.Generate(checker, rand.NewSource(seed))
+			c.Assert(err, gc.IsNil, gc.Commentf("schema %d, seed %d", i, seed))
+			_, err = checker.Coerce(v, nil)
+			c.Assert(err, gc.IsNil, gc.Commentf("schema %d, seed %d", i, seed))
+		}
+	}
+}
+
+func (s *S) TestGenerateConstrainedCollections(c *gc.C) {
+	letters := schema.OneOf(schema.Const("a"), schema.Const("b"), schema.Const("c"), schema.Const("d"), schema.Const("e"))
+	for i, checker := range []schema.Checker{
+		schema.ListWith(letters, schema.ListOptions{MinLen: 5, MaxLen: 5, Unique: true}),
+		schema.ListWith(schema.Int(), schema.ListOptions{
+			MinLen: 3,
+			MaxLen: 3,
+			UniqueKey: func(elem interface{}) interface{} {
+				return elem.(int64) % 3
+			},
+		}),
+		schema.ListWith(schema.Set(letters), schema.ListOptions{MinLen: 5, MaxLen: 5}),
+		schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{KeyPattern: regexp.MustCompile(`^[A-Z]{3}-[0-9]{4}$`)}),
+		schema.StringMapWith(schema.Int(), schema.StringMapOptions{KeyPattern: regexp.MustCompile(`^(eth|wlan)[0-9]+$`)}),
+	} {
+		for seed := int64(0); seed < 20; seed++ {
+			comment := gc.Commentf("schema %d, seed %d", i, seed)
+			v, err := schema.Generate(checker, rand.NewSource(seed))
+			c.Assert(err, gc.IsNil, comment)
+			_, err = checker.Coerce(v, nil)
+			c.Assert(err, gc.IsNil, comment)
+
+			v, path, err := schema.GenerateInvalid(checker, rand.NewSource(seed))
+			c.Assert(err, gc.IsNil, comment)
+			_, err = checker.Coerce(v, nil)
+			c.Assert(err, gc.NotNil, comment)
+			c.Assert(strings.HasPrefix(err.Error(), path), gc.Equals, true, comment)
+		}
+	}
+}
+
+func (s *S) TestGenerateInvalidUnsatisfiableList(c *gc.C) {
+	checker := schema.ListWith(schema.Const("x"), schema.ListOptions{MinLen: 2, MaxLen: 2, Unique: true})
+	_, err := schema.Generate(checker, rand.NewSource(1))
+	c.Assert(err, gc.ErrorMatches, `cannot generate 2 elements that are distinct`)
+
+	v, path, err := schema.GenerateInvalid(checker, rand.NewSource(1))
+	c.Assert(err, gc.IsNil)
+	c.Assert(path, gc.Equals, "")
+	_, err = checker.Coerce(v, nil)
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *S) TestGenerateDeterministic(c *gc.C) {
+	checker := generateSchemas[len(generateSchemas)-2]
+	v1, err := schema.Generate(checker, rand.NewSource(42))
+	c.Assert(err, gc.IsNil)
+	v2, err := schema.Generate(checker, rand.NewSource(42))
+	c.Assert(err, gc.IsNil)
+	c.Assert(v1, gc.DeepEquals, v2)
+}
+
+func (s *S) TestGenerateInvalid(c *gc.C) {
+	for i, checker := range generateSchemas[1:] {
+		for seed := int64(0); seed < 20; seed++ {
+			comment := gc.Commentf("schema %d, seed %d", i+1, seed)
+			v, path, err := schema.GenerateInvalid(checker, rand.NewSource(seed))
+			c.Assert(err, gc.IsNil, comment)
+			_, err = checker.Coerce(v, nil)
+			c.Assert(err, gc.NotNil, comment)
+			c.Assert(strings.HasPrefix(err.Error(), path), gc.Equals, true, comment)
+		}
+	}
+}
+
+func (s *S) TestGenerateInvalidPath(c *gc.C) {
+	checker := schema.FieldMap(schema.Fields{
+		"units": schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 1}),
+	}, nil)
+	v, path, err := schema.GenerateInvalid(checker, rand.NewSource(1))
+	c.Assert(err, gc.IsNil)
+	c.Assert(path, gc.Matches, `units\[\d\]`)
+	_, err = checker.Coerce(v, nil)
+	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta(path)+`: expected int, got .*`)
+}
+
+// pathlessC accepts strings, and reports errors without their path.
+type pathlessC struct{}
+
+func (pathlessC) Coerce(v interface{}, path []string) (interface{}, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("expected string, got %T", v)
+}
+
+func (s *S) TestGenerateInvalidPathMismatch(c *gc.C) {
+	checker := schema.FieldMap(schema.Fields{"name": pathlessC{}}, nil)
+	for seed := int64(0); seed < 20; seed++ {
+		comment := gc.Commentf("seed %d", seed)
+		v, path, err := schema.GenerateInvalid(checker, rand.NewSource(seed))
+		if err != nil {
+			c.Assert(err, gc.ErrorMatches, `cannot generate an invalid value for map`, comment)
+			continue
+		}
+		// The name field is never reported as invalid, since errors
+		// about it don't hold its path.
+		c.Assert(path, gc.Equals, "", comment)
+		_, err = checker.Coerce(v, nil)
+		c.Assert(err, gc.NotNil, comment)
+	}
+}
+
+func (s *S) TestGenerateInvalidAny(c *gc.C) {
+	_, _, err := schema.GenerateInvalid(schema.Any(), rand.NewSource(1))
+	c.Assert(err, gc.ErrorMatches, `cannot generate an invalid value for any value`)
+}
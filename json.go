@@ -0,0 +1,121 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+)
+
+// JSONCompatible returns a Checker that processes the value with the
+// provided checker, and then converts the coerced value so that it can
+// be marshalled by encoding/json. Maps found at any depth, such as the
+// map[interface{}]interface{} values produced by YAML decoders and by
+// Map, are converted to map[string]interface{}, and lists holding maps
+// are converted to []interface{}. Processing fails if a map has a key
+// that is not a string.
+//
+// If checker is nil, Any is used.
+func JSONCompatible(checker Checker) Checker {
+	if checker == nil {
+		checker = Any()
+	}
+	return jsonC{checker}
+}
+
+type jsonC struct {
+	checker Checker
+}
+
+func (c jsonC) Coerce(v interface{}, path []string) (interface{}, error) {
+	newv, err := c.checker.Coerce(v, path)
+	if err != nil {
+		return nil, err
+	}
+	return jsonValue(newv, path)
+}
+
+// Uncoerce implements Encoder.
+func (c jsonC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	return uncoerce(c.checker, v, path)
+}
+
+func (c jsonC) holdsSecret() bool {
+	return holdsSecret(c.checker)
+}
+
+func (c jsonC) redact(v interface{}) interface{} {
+	return redact(c.checker, v)
+}
+
+// jsonValue returns v with all the maps it holds converted to
+// map[string]interface{}.
+func jsonValue(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return v, nil
+		}
+		vpath := append(path, ".", "?")
+		out := make(map[string]interface{}, rv.Len())
+		for _, k := range sortedKeys(rv) {
+			kv := k
+			if kv.Kind() == reflect.Interface {
+				kv = kv.Elem()
+			}
+			if kv.Kind() != reflect.String {
+				return nil, error_{"string", k.Interface(), keyPath(path, k.Interface())}
+			}
+			ks := kv.String()
+			vpath[len(vpath)-1] = ks
+			newv, err := jsonValue(rv.MapIndex(k).Interface(), vpath)
+			if err != nil {
+				return nil, err
+			}
+			out[ks] = newv
+		}
+		return out, nil
+	case reflect.Slice, reflect.Array:
+		switch rv.Type().Elem().Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice, reflect.Array:
+		default:
+			return v, nil
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return v, nil
+		}
+		epath := append(path, "[", "?", "]")
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			epath[len(epath)-2] = strconv.Itoa(i)
+			elem, err := jsonValue(rv.Index(i).Interface(), epath)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = elem
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// Describe implements Descriptor.
+func (c jsonC) Describe() Description {
+	elem := describePtr(c.checker)
+	return Description{Kind: KindJSON, Label: elem.Label, Elem: elem}
+}
+
+func (c jsonC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return example(c.checker, opts, path)
+}
+
+func (c jsonC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return generate(c.checker, r, path)
+}
+
+func (c jsonC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	return generateInvalid(c.checker, r, path)
+}
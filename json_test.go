@@ -0,0 +1,70 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	"encoding/json"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestJSONCompatible(c *gc.C) {
+	sch := schema.JSONCompatible(nil)
+
+	in := map[interface{}]interface{}{
+		"name": "app",
+		"labels": map[interface{}]interface{}{
+			"tier": "web",
+		},
+		"ports": []interface{}{
+			80,
+			map[interface{}]interface{}{"port": 443, "tls": true},
+		},
+		"sizes": []int{1, 2},
+	}
+	out, err := sch.Coerce(in, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"name": "app",
+		"labels": map[string]interface{}{
+			"tier": "web",
+		},
+		"ports": []interface{}{
+			80,
+			map[string]interface{}{"port": 443, "tls": true},
+		},
+		"sizes": []int{1, 2},
+	})
+	data, err := json.Marshal(out)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, `{"labels":{"tier":"web"},"name":"app","ports":[80,{"port":443,"tls":true}],"sizes":[1,2]}`)
+
+	out, err = sch.Coerce("plain", aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "plain")
+}
+
+func (s *S) TestJSONCompatibleNonStringKey(c *gc.C) {
+	sch := schema.JSONCompatible(schema.Any())
+
+	_, err := sch.Coerce(map[interface{}]interface{}{
+		"ports": []interface{}{
+			map[interface{}]interface{}{"a": map[interface{}]interface{}{1: "x"}},
+		},
+	}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.ports\[0\]\.a\{1\}: expected string, got int\(1\)`)
+}
+
+func (s *S) TestJSONCompatibleWithChecker(c *gc.C) {
+	sch := schema.JSONCompatible(schema.Map(schema.String(), schema.Int()))
+
+	out, err := sch.Coerce(map[interface{}]interface{}{"a": "1"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": int64(1)})
+
+	_, err = sch.Coerce(map[interface{}]interface{}{"a": true}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a: expected int, got bool\(true\)`)
+}
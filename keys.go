@@ -0,0 +1,103 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyNormalizer returns the normalized form of a map key. Keys that
+// normalize to the same string are considered to be the same key.
+type KeyNormalizer func(key string) string
+
+// FoldKeyCase is a KeyNormalizer that makes keys case-insensitive.
+func FoldKeyCase(key string) string {
+	return strings.ToLower(key)
+}
+
+// FoldKeySeparators is a KeyNormalizer that ignores dashes and
+// underscores in keys, so that "max-units", "max_units" and "maxunits"
+// are all the same key.
+func FoldKeySeparators(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, key)
+}
+
+// NormalizeKeys returns a KeyNormalizer that applies each of the
+// provided normalizers in turn. For example,
+// NormalizeKeys(FoldKeyCase, FoldKeySeparators) makes "MaxUnits" and
+// "max-units" the same key.
+func NormalizeKeys(normalizers ...KeyNormalizer) KeyNormalizer {
+	return func(key string) string {
+		for _, normalize := range normalizers {
+			key = normalize(key)
+		}
+		return key
+	}
+}
+
+// suggestKeys returns the candidates closest to key by edit distance,
+// provided they are close enough to be a plausible misspelling of it.
+// The result is sorted.
+func suggestKeys(key string, candidates []string) []string {
+	best := len([]rune(key)) / 3
+	var suggestions []string
+	for _, candidate := range candidates {
+		d := editDistance(key, candidate)
+		if d > best || d >= len([]rune(candidate)) {
+			continue
+		}
+		if d < best {
+			best = d
+			suggestions = suggestions[:0]
+		}
+		suggestions = append(suggestions, candidate)
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// editDistance returns the number of single character insertions,
+// deletions, substitutions and transpositions of adjacent characters
+// needed to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] holds the distance between ra[:i] and rb[:j].
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
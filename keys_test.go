@@ -0,0 +1,41 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(&keysSuite{})
+
+type keysSuite struct{}
+
+func (*keysSuite) TestEditDistance(c *gc.C) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"replcas", "replicas", 1},
+		{"replicsa", "replicas", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %q %q", i, test.a, test.b)
+		c.Check(editDistance(test.a, test.b), gc.Equals, test.want)
+		c.Check(editDistance(test.b, test.a), gc.Equals, test.want)
+	}
+}
+
+func (*keysSuite) TestSuggestKeys(c *gc.C) {
+	candidates := []string{"a", "b", "name", "names", "replicas"}
+	c.Check(suggestKeys("d", candidates), gc.HasLen, 0)
+	c.Check(suggestKeys("nmae", candidates), gc.DeepEquals, []string{"name"})
+	c.Check(suggestKeys("namez", candidates), gc.DeepEquals, []string{"name", "names"})
+	c.Check(suggestKeys("replica", candidates), gc.DeepEquals, []string{"replicas"})
+	c.Check(suggestKeys("unrelated", candidates), gc.HasLen, 0)
+}
@@ -4,8 +4,12 @@
 package schema
 
 import (
+	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // List returns a Checker that accepts a slice value with values
@@ -15,26 +19,403 @@ import (
 //
 // The coerced output value has type []interface{}.
 func List(elem Checker) Checker {
-	return listC{elem}
+	return listC{elem: elem, secret: holdsSecret(elem)}
+}
+
+// ListOptions holds optional behaviour for the Checker returned by
+// ListWith.
+type ListOptions struct {
+	// MinLen holds the minimum number of elements accepted.
+	MinLen int
+
+	// MaxLen, if not zero, holds the maximum number of elements
+	// accepted.
+	MaxLen int
+
+	// Unique causes processing to fail if two elements have the
+	// same coerced value.
+	Unique bool
+
+	// UniqueKey, if not nil, causes processing to fail if it returns
+	// the same key for two coerced elements. It implies Unique.
+	UniqueKey func(elem interface{}) interface{}
+
+	// Contains, if not nil, causes processing to fail unless at
+	// least one coerced element is accepted by it.
+	Contains Checker
+
+	// Arrays causes array values to be accepted as well as slices.
+	Arrays bool
+
+	// Iterators causes iterator functions, of type
+	// func(yield func(T) bool) such as iter.Seq[T], to be accepted as
+	// well as slices. The values they produce are processed as the
+	// elements of the list. When MaxLen is set, iteration stops as
+	// soon as too many elements were produced.
+	Iterators bool
+
+	// Single causes a value that is not a list to be accepted as a
+	// list holding that value as its only element. Nil values are
+	// still rejected.
+	Single bool
+
+	// Separator, if not empty, causes a string value to be split
+	// around it, and the resulting substrings to be processed as the
+	// elements of the list, with surrounding white space removed. An
+	// empty string results in an empty list.
+	Separator string
+
+	// Type, if not nil, holds the slice type of the coerced output
+	// value, such as []string, instead of []interface{}. Processing
+	// fails if a coerced element is not assignable to its element
+	// type.
+	Type reflect.Type
+}
+
+// ListWith returns a Checker that acts as the one returned by List,
+// with additional behaviour configured by opts. It panics if opts.Type
+// is set to a type that is not a slice type.
+func ListWith(elem Checker, opts ListOptions) Checker {
+	if opts.Type != nil && opts.Type.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("ListWith got non-slice output type %s", opts.Type))
+	}
+	return listC{elem: elem, opts: opts, secret: anySecret(elem, opts.Contains)}
+}
+
+// Set returns a Checker that accepts a slice value with values that
+// are processed with the elem checker, as done by List. Duplicate
+// coerced values are removed, and the remaining ones are sorted.
+//
+// The coerced output value has type []interface{}.
+func Set(elem Checker) Checker {
+	return listC{elem: elem, set: true, secret: holdsSecret(elem)}
 }
 
 type listC struct {
 	elem Checker
+	opts ListOptions
+
+	// set causes duplicates to be removed and the elements to be
+	// sorted.
+	set bool
+
+	// secret holds whether any of the checkers is secret.
+	secret bool
 }
 
 func (c listC) Coerce(v interface{}, path []string) (interface{}, error) {
+	rv, ok := c.elements(v)
+	if !ok {
+		return nil, error_{"list", errorValue(c, v), path}
+	}
+
+	l := rv.Len()
+	if l < c.opts.MinLen {
+		return nil, error_{fmt.Sprintf("list with at least %s", elements(c.opts.MinLen)), errorValue(c, v), path}
+	}
+	if c.opts.MaxLen > 0 && l > c.opts.MaxLen {
+		return nil, error_{fmt.Sprintf("list with at most %s", elements(c.opts.MaxLen)), errorValue(c, v), path}
+	}
+
+	epath := append(path, "[", "?", "]")
+
+	unique := c.unique()
+	seen := make(map[interface{}]int)
+	out := make([]interface{}, 0, l)
+	for i := 0; i != l; i++ {
+		epath[len(epath)-2] = strconv.Itoa(i)
+		elem, err := c.elem.Coerce(rv.Index(i).Interface(), epath)
+		if err != nil {
+			return nil, err
+		}
+		if unique {
+			key := c.key(elem)
+			if j, ok := seen[key]; ok {
+				if c.set {
+					continue
+				}
+				return nil, fmt.Errorf("%sduplicate of element %d", pathAsPrefix(epath), j)
+			}
+			seen[key] = i
+		}
+		out = append(out, elem)
+	}
+	if c.opts.Contains != nil && !containsMatch(c.opts.Contains, out) {
+		return nil, error_{"list containing a matching element", errorValue(c, v), path}
+	}
+	if c.set {
+		sort.SliceStable(out, func(i, j int) bool {
+			return lessValue(out[i], out[j])
+		})
+	}
+	if c.opts.Type != nil {
+		typed := reflect.MakeSlice(c.opts.Type, len(out), len(out))
+		for i, elem := range out {
+			epath[len(epath)-2] = strconv.Itoa(i)
+			ev, err := assignableValue(c.opts.Type.Elem(), elem, epath)
+			if err != nil {
+				return nil, secretError(c.elem, err, epath)
+			}
+			typed.Index(i).Set(ev)
+		}
+		return typed.Interface(), nil
+	}
+	return out, nil
+}
+
+// unique reports whether the elements of the list must be distinct.
+func (c listC) unique() bool {
+	return c.opts.Unique || c.opts.UniqueKey != nil || c.set
+}
+
+// key returns the key identifying elem, a coerced element, when
+// checking that elements are distinct.
+func (c listC) key(elem interface{}) interface{} {
+	if c.opts.UniqueKey != nil {
+		elem = c.opts.UniqueKey(elem)
+	}
+	return hashable(elem)
+}
+
+// Uncoerce implements Encoder.
+func (c listC) Uncoerce(v interface{}, path []string) (interface{}, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Slice {
-		return nil, error_{"list", v, path}
+		return nil, error_{"list", errorValue(c, v), path}
 	}
+	epath := append(path, "[", "?", "]")
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		epath[len(epath)-2] = strconv.Itoa(i)
+		elem, err := uncoerce(c.elem, rv.Index(i).Interface(), epath)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = elem
+	}
+	return out, nil
+}
 
-	path = append(path, "[", "?", "]")
+func (c listC) holdsSecret() bool {
+	return c.secret
+}
 
+func (c listC) redact(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v
+	}
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = redact(c.elem, rv.Index(i).Interface())
+	}
+	return out
+}
+
+// elements returns the value holding the elements of the list v, and
+// whether v is accepted as a list at all.
+func (c listC) elements(v interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.Slice:
+		return rv, true
+	case rv.Kind() == reflect.Array && c.opts.Arrays:
+		return rv, true
+	case rv.Kind() == reflect.Func && c.opts.Iterators && !rv.IsNil() && isSeq(rv.Type()):
+		return c.collect(rv), true
+	case rv.Kind() == reflect.String && c.opts.Separator != "":
+		var elems []string
+		if s := rv.String(); s != "" {
+			elems = strings.Split(s, c.opts.Separator)
+		}
+		for i, elem := range elems {
+			elems[i] = strings.TrimSpace(elem)
+		}
+		return reflect.ValueOf(elems), true
+	case v != nil && c.opts.Single:
+		return reflect.ValueOf([]interface{}{v}), true
+	}
+	return rv, false
+}
+
+// isSeq reports whether t is the type of an iterator function, such as
+// iter.Seq[T], that calls yield with each element in turn.
+func isSeq(t reflect.Type) bool {
+	if t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func && yield.NumIn() == 1 && yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
+
+// collect returns a slice holding the values produced by seq, an
+// iterator function. At most MaxLen+1 values are collected when
+// MaxLen is set, so that too long lists are still rejected.
+func (c listC) collect(seq reflect.Value) reflect.Value {
+	yieldType := seq.Type().In(0)
+	elems := reflect.MakeSlice(reflect.SliceOf(yieldType.In(0)), 0, 0)
+	yield := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+		elems = reflect.Append(elems, args[0])
+		more := c.opts.MaxLen == 0 || elems.Len() <= c.opts.MaxLen
+		return []reflect.Value{reflect.ValueOf(more).Convert(yieldType.Out(0))}
+	})
+	seq.Call([]reflect.Value{yield})
+	return elems
+}
+
+func containsMatch(checker Checker, elems []interface{}) bool {
+	for _, elem := range elems {
+		if _, err := checker.Coerce(elem, nil); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hashable returns v if it can be used as a map key, or a string
+// representation of it otherwise.
+func hashable(v interface{}) interface{} {
+	if v == nil || reflect.TypeOf(v).Comparable() {
+		return v
+	}
+	return fmt.Sprintf("%T %#v", v, v)
+}
+
+// lessValue defines the order of the elements returned by Set. Values
+// are ordered first by kind, and then numbers, strings and booleans
+// are ordered naturally, while other values are ordered by their
+// string representation.
+func lessValue(a, b interface{}) bool {
+	ra, rb := valueRank(a), valueRank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	switch ra {
+	case 1:
+		return !reflect.ValueOf(a).Bool() && reflect.ValueOf(b).Bool()
+	case 2:
+		va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+		switch {
+		case isInt(va) && isInt(vb):
+			return va.Int() < vb.Int()
+		case isUint(va) && isUint(vb):
+			return va.Uint() < vb.Uint()
+		}
+		return toFloat(va) < toFloat(vb)
+	case 3:
+		return reflect.ValueOf(a).String() < reflect.ValueOf(b).String()
+	}
+	return fmt.Sprintf("%#v", a) < fmt.Sprintf("%#v", b)
+}
+
+// valueRank returns the rank of the kind of v in the order used by
+// lessValue.
+func valueRank(v interface{}) int {
+	if v == nil {
+		return 0
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.Bool:
+		return 1
+	case isInt(rv), isUint(rv), rv.Kind() == reflect.Float32, rv.Kind() == reflect.Float64:
+		return 2
+	case rv.Kind() == reflect.String:
+		return 3
+	}
+	return 4
+}
+
+func isInt(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func toFloat(rv reflect.Value) float64 {
+	switch {
+	case isInt(rv):
+		return float64(rv.Int())
+	case isUint(rv):
+		return float64(rv.Uint())
+	}
+	return rv.Float()
+}
+
+// Tuple returns a Checker that accepts a slice value with exactly one
+// element per provided checker, each element being processed with the
+// checker at the same index. If any element fails to be processed,
+// processing will stop and return with the obtained error.
+//
+// The coerced output value has type []interface{}.
+func Tuple(items ...Checker) Checker {
+	return TupleWith(items, TupleOptions{})
+}
+
+// TupleOptions holds optional behaviour for the Checker returned by
+// TupleWith.
+type TupleOptions struct {
+	// Optional holds the number of trailing items that may be missing
+	// from the slice, in which case they are missing from the coerced
+	// slice too. If it is zero, all the items are required.
+	Optional int
+
+	// Rest, if not nil, is used to process the elements beyond the
+	// ones covered by the items, which are rejected otherwise.
+	Rest Checker
+}
+
+// TupleWith returns a Checker that acts as the one returned by Tuple,
+// with additional behaviour configured by opts.
+func TupleWith(items []Checker, opts TupleOptions) Checker {
+	minLen := len(items) - opts.Optional
+	if minLen < 0 {
+		minLen = 0
+	}
+	return tupleC{items, opts, minLen, holdsSecret(opts.Rest) || anySecret(items...)}
+}
+
+type tupleC struct {
+	items []Checker
+	opts  TupleOptions
+
+	// minLen holds the number of required items.
+	minLen int
+
+	// secret holds whether any of the checkers is secret.
+	secret bool
+}
+
+func (c tupleC) Coerce(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, error_{"list", errorValue(c, v), path}
+	}
 	l := rv.Len()
+	if l < c.minLen || (c.opts.Rest == nil && l > len(c.items)) {
+		return nil, error_{c.expected(), errorValue(c, v), path}
+	}
+
+	path = append(path, "[", "?", "]")
+
 	out := make([]interface{}, 0, l)
 	for i := 0; i != l; i++ {
+		checker := c.opts.Rest
+		if i < len(c.items) {
+			checker = c.items[i]
+		}
 		path[len(path)-2] = strconv.Itoa(i)
-		elem, err := c.elem.Coerce(rv.Index(i).Interface(), path)
+		elem, err := checker.Coerce(rv.Index(i).Interface(), path)
 		if err != nil {
 			return nil, err
 		}
@@ -42,3 +423,251 @@ func (c listC) Coerce(v interface{}, path []string) (interface{}, error) {
 	}
 	return out, nil
 }
+
+// Uncoerce implements Encoder.
+func (c tupleC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, error_{"list", errorValue(c, v), path}
+	}
+	if rv.Len() > len(c.items) && c.opts.Rest == nil {
+		return nil, error_{c.expected(), errorValue(c, v), path}
+	}
+	epath := append(path, "[", "?", "]")
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		checker := c.opts.Rest
+		if i < len(c.items) {
+			checker = c.items[i]
+		}
+		epath[len(epath)-2] = strconv.Itoa(i)
+		elem, err := uncoerce(checker, rv.Index(i).Interface(), epath)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = elem
+	}
+	return out, nil
+}
+
+func (c tupleC) holdsSecret() bool {
+	return c.secret
+}
+
+func (c tupleC) redact(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v
+	}
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		elem := rv.Index(i).Interface()
+		switch {
+		case i < len(c.items):
+			out[i] = redact(c.items[i], elem)
+		case c.opts.Rest != nil:
+			out[i] = redact(c.opts.Rest, elem)
+		default:
+			out[i] = elem
+		}
+	}
+	return out
+}
+
+// expected returns the label describing the accepted lengths.
+func (c tupleC) expected() string {
+	switch {
+	case c.opts.Rest != nil:
+		return fmt.Sprintf("list with at least %s", elements(c.minLen))
+	case c.minLen < len(c.items):
+		return fmt.Sprintf("list with %d to %d elements", c.minLen, len(c.items))
+	}
+	return fmt.Sprintf("list with %s", elements(len(c.items)))
+}
+
+func elements(n int) string {
+	if n == 1 {
+		return "1 element"
+	}
+	return fmt.Sprintf("%d elements", n)
+}
+
+// Describe implements Descriptor.
+func (c listC) Describe() Description {
+	d := Description{Kind: KindList, Elem: describePtr(c.elem)}
+	d.Label = "list of " + d.Elem.Label
+	if c.set {
+		d.Kind = KindSet
+		d.Label = "set of " + d.Elem.Label
+	}
+	d.MinLen = c.opts.MinLen
+	d.MaxLen = c.opts.MaxLen
+	d.Constraints = boundConstraints(d)
+	if c.opts.Unique || c.opts.UniqueKey != nil {
+		d.Constraints = append(d.Constraints, "unique")
+	}
+	if c.opts.Contains != nil {
+		d.Constraints = append(d.Constraints, "contains "+Describe(c.opts.Contains).Label)
+	}
+	return d
+}
+
+// Describe implements Descriptor.
+func (c tupleC) Describe() Description {
+	d := Description{Kind: KindTuple, Items: describeAll(c.items)}
+	label := joinLabels(d.Items, ", ")
+	if c.opts.Rest != nil {
+		d.Elem = describePtr(c.opts.Rest)
+		label += ", " + d.Elem.Label + "..."
+	}
+	d.Label = "tuple of " + label
+	d.MinLen = c.minLen
+	if c.minLen < len(c.items) {
+		d.Constraints = append(d.Constraints, "at least "+elements(c.minLen))
+	}
+	return d
+}
+
+func (c listC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	n := c.opts.MinLen
+	if n == 0 && (c.opts.Contains != nil || opts.AllFields) {
+		n = 1
+	}
+	contains := -1
+	if c.opts.Contains != nil {
+		contains = 0
+	}
+	// When the elements must be distinct, the example is used first
+	// and random values generated from a fixed seed afterwards, so
+	// that the example stays the same from one call to the next.
+	return c.elems(n, contains, path, func(checker Checker, epath []string, attempt int) (interface{}, error) {
+		if attempt == 0 {
+			return example(checker, opts, epath)
+		}
+		return generate(checker, rand.New(rand.NewSource(int64(attempt))), epath)
+	})
+}
+
+// elems returns n elements for a list accepted by c, produced by
+// calling elem with the checker for each element. The element at index
+// contains is produced with the Contains checker. When the elements must
+// be distinct, elem is called again with an increasing attempt number
+// until it produces one that is not a duplicate.
+func (c listC) elems(n, contains int, path []string, elem func(checker Checker, epath []string, attempt int) (interface{}, error)) ([]interface{}, error) {
+	out := make([]interface{}, n)
+	seen := make(map[interface{}]bool)
+	for i := range out {
+		checker := c.elem
+		if i == contains {
+			checker = c.opts.Contains
+		}
+		epath := childPath(path, "[", strconv.Itoa(i), "]")
+		for attempt := 0; ; attempt++ {
+			v, err := elem(checker, epath, attempt)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+			if !c.unique() {
+				break
+			}
+			// Invalid elements are left for the caller to report.
+			cv, err := c.elem.Coerce(v, epath)
+			if err != nil {
+				break
+			}
+			if key := c.key(cv); !seen[key] {
+				seen[key] = true
+				break
+			}
+			if attempt == generateAttempts {
+				return nil, fmt.Errorf("%scannot generate %s that are distinct", pathAsPrefix(path), elements(n))
+			}
+		}
+	}
+	return out, nil
+}
+
+func (c tupleC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	out := make([]interface{}, len(c.items))
+	epath := append(path, "[", "?", "]")
+	for i, checker := range c.items {
+		epath[len(epath)-2] = strconv.Itoa(i)
+		elem, err := example(checker, opts, epath)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = elem
+	}
+	return out, nil
+}
+
+func (c listC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	n := c.opts.MinLen + r.Intn(4)
+	if c.opts.MaxLen > 0 && n > c.opts.MaxLen {
+		n = c.opts.MaxLen
+	}
+	contains := -1
+	if c.opts.Contains != nil {
+		if n == 0 {
+			n = 1
+		}
+		contains = r.Intn(n)
+	}
+	return c.elems(n, contains, path, func(checker Checker, epath []string, attempt int) (interface{}, error) {
+		return generate(checker, r, epath)
+	})
+}
+
+func (c listC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	v, err := c.generate(r, path)
+	if err != nil {
+		return invalidLeaf(c, r, path)
+	}
+	out := v.([]interface{})
+	if len(out) == 0 {
+		out = append(out, nil)
+	}
+	i := r.Intn(len(out))
+	elem, epath, ok := generateInvalid(c.elem, r, childPath(path, "[", strconv.Itoa(i), "]"))
+	if !ok {
+		return invalidLeaf(c, r, path)
+	}
+	out[i] = elem
+	return out, epath, true
+}
+
+func (c tupleC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	n := len(c.items)
+	if c.opts.Rest != nil {
+		n += r.Intn(3)
+	}
+	out := make([]interface{}, n)
+	for i := range out {
+		checker := c.opts.Rest
+		if i < len(c.items) {
+			checker = c.items[i]
+		}
+		elem, err := generate(checker, r, childPath(path, "[", strconv.Itoa(i), "]"))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = elem
+	}
+	return out, nil
+}
+
+func (c tupleC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	v, err := c.generate(r, path)
+	if err != nil || len(c.items) == 0 {
+		return invalidLeaf(c, r, path)
+	}
+	out := v.([]interface{})
+	i := r.Intn(len(c.items))
+	elem, epath, ok := generateInvalid(c.items[i], r, childPath(path, "[", strconv.Itoa(i), "]"))
+	if !ok {
+		return invalidLeaf(c, r, path)
+	}
+	out[i] = elem
+	return out, epath, true
+}
@@ -5,89 +5,513 @@ package schema
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
+	"regexp"
+	"sort"
 )
 
 // Map returns a Checker that accepts a map value. Every key and value
 // in the map are processed with the respective checker, and if any
 // value fails to be coerced, processing stops and returns with the
-// underlying error.
+// underlying error. Keys are processed in sorted order, so that the
+// error returned is always the same for a given map, and errors about
+// a key are reported with a path such as "labels{3}".
 //
 // The coerced output value has type map[interface{}]interface{}.
 func Map(key Checker, value Checker) Checker {
-	return mapC{key, value}
+	return MapWith(key, value, MapOptions{})
+}
+
+// MapOptions holds optional behaviour for the Checker returned by
+// MapWith.
+type MapOptions struct {
+	// KeyPattern, if not nil, must match the string representation
+	// of every coerced key.
+	KeyPattern *regexp.Regexp
+
+	// MaxKeys, if not zero, holds the maximum number of keys
+	// accepted.
+	MaxKeys int
+
+	// Type, if not nil, holds the map type of the coerced output
+	// value, such as map[string]int64, instead of
+	// map[interface{}]interface{}. Processing fails if a coerced key
+	// or value is not assignable to its key or element type.
+	Type reflect.Type
+}
+
+// MapWith returns a Checker that acts as the one returned by Map, with
+// additional behaviour configured by opts. It panics if opts.Type is
+// set to a type that is not a map type.
+func MapWith(key Checker, value Checker, opts MapOptions) Checker {
+	if opts.Type != nil && opts.Type.Kind() != reflect.Map {
+		panic(fmt.Sprintf("MapWith got non-map output type %s", opts.Type))
+	}
+	return mapC{key, value, opts, anySecret(key, value)}
 }
 
 type mapC struct {
 	key   Checker
 	value Checker
+	opts  MapOptions
+
+	// secret holds whether any of the checkers is secret.
+	secret bool
 }
 
 func (c mapC) Coerce(v interface{}, path []string) (interface{}, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Map {
-		return nil, error_{"map", v, path}
+		return nil, error_{"map", errorValue(c, v), path}
+	}
+
+	l := rv.Len()
+	if c.opts.MaxKeys > 0 && l > c.opts.MaxKeys {
+		return nil, error_{fmt.Sprintf("map with at most %s", keysLabel(c.opts.MaxKeys)), errorValue(c, v), path}
 	}
 
 	vpath := append(path, ".", "?")
 
-	l := rv.Len()
 	out := make(map[interface{}]interface{}, l)
-	keys := rv.MapKeys()
-	for i := 0; i != l; i++ {
-		k := keys[i]
-		newk, err := c.key.Coerce(k.Interface(), path)
+	var typed reflect.Value
+	if c.opts.Type != nil {
+		typed = reflect.MakeMapWithSize(c.opts.Type, l)
+	}
+	for _, k := range sortedKeys(rv) {
+		kpath := keyPath(path, k.Interface())
+		newk, err := c.key.Coerce(k.Interface(), kpath)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkKey(c.opts.KeyPattern, newk, kpath); err != nil {
+			return nil, err
+		}
 		vpath[len(vpath)-1] = fmt.Sprint(k.Interface())
 		newv, err := c.value.Coerce(rv.MapIndex(k).Interface(), vpath)
 		if err != nil {
 			return nil, err
 		}
+		if !typed.IsValid() {
+			out[newk] = newv
+			continue
+		}
+		tk, err := assignableValue(c.opts.Type.Key(), newk, kpath)
+		if err != nil {
+			return nil, secretError(c.key, err, kpath)
+		}
+		tv, err := assignableValue(c.opts.Type.Elem(), newv, vpath)
+		if err != nil {
+			return nil, secretError(c.value, err, vpath)
+		}
+		typed.SetMapIndex(tk, tv)
+	}
+	if typed.IsValid() {
+		return typed.Interface(), nil
+	}
+	return out, nil
+}
+
+// Uncoerce implements Encoder.
+func (c mapC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, error_{"map", errorValue(c, v), path}
+	}
+	vpath := append(path, ".", "?")
+	out := make(map[interface{}]interface{}, rv.Len())
+	for _, k := range sortedKeys(rv) {
+		newk, err := uncoerce(c.key, k.Interface(), keyPath(path, k.Interface()))
+		if err != nil {
+			return nil, err
+		}
+		vpath[len(vpath)-1] = fmt.Sprint(k.Interface())
+		newv, err := uncoerce(c.value, rv.MapIndex(k).Interface(), vpath)
+		if err != nil {
+			return nil, err
+		}
 		out[newk] = newv
 	}
 	return out, nil
 }
 
+func (c mapC) holdsSecret() bool {
+	return c.secret
+}
+
+func (c mapC) redact(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return v
+	}
+	out := make(map[interface{}]interface{}, rv.Len())
+	for _, k := range rv.MapKeys() {
+		out[k.Interface()] = redact(c.value, rv.MapIndex(k).Interface())
+	}
+	return out
+}
+
 // StringMap returns a Checker that accepts a map value. Every key in
 // the map must be a string, and every value in the map are processed
 // with the provided checker. If any value fails to be coerced,
-// processing stops and returns with the underlying error.
+// processing stops and returns with the underlying error. Keys are
+// processed in sorted order, as done by Map.
 //
 // The coerced output value has type map[string]interface{}.
 func StringMap(value Checker) Checker {
-	return stringMapC{value}
+	return StringMapWith(value, StringMapOptions{})
+}
+
+// StringMapOptions holds optional behaviour for the Checker returned
+// by StringMapWith.
+type StringMapOptions struct {
+	// NormalizeKey, if not nil, is applied to every key in the map.
+	// The normalized keys are used in the coerced map and in error
+	// messages, and processing fails if several keys normalize to
+	// the same one.
+	NormalizeKey KeyNormalizer
+
+	// KeyPattern, if not nil, must match every key, after
+	// normalization.
+	KeyPattern *regexp.Regexp
+
+	// MaxKeys, if not zero, holds the maximum number of keys
+	// accepted.
+	MaxKeys int
+
+	// Type, if not nil, holds the map type of the coerced output
+	// value, such as map[string]int64, instead of
+	// map[string]interface{}. Its key type must be a string type.
+	// Processing fails if a coerced value is not assignable to its
+	// element type.
+	Type reflect.Type
+}
+
+// StringMapWith returns a Checker that acts as the one returned by
+// StringMap, with additional behaviour configured by opts. It panics
+// if opts.Type is set to a type that is not a map type with string
+// keys.
+func StringMapWith(value Checker, opts StringMapOptions) Checker {
+	if opts.Type != nil && (opts.Type.Kind() != reflect.Map || opts.Type.Key().Kind() != reflect.String) {
+		panic(fmt.Sprintf("StringMapWith got non-string-keyed map output type %s", opts.Type))
+	}
+	return stringMapC{value, opts, holdsSecret(value)}
 }
 
 type stringMapC struct {
 	value Checker
+	opts  StringMapOptions
+
+	// secret holds whether the value checker is secret.
+	secret bool
 }
 
 func (c stringMapC) Coerce(v interface{}, path []string) (interface{}, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Map {
-		return nil, error_{"map", v, path}
+		return nil, error_{"map", errorValue(c, v), path}
+	}
+
+	l := rv.Len()
+	if c.opts.MaxKeys > 0 && l > c.opts.MaxKeys {
+		return nil, error_{fmt.Sprintf("map with at most %s", keysLabel(c.opts.MaxKeys)), errorValue(c, v), path}
 	}
 
 	vpath := append(path, ".", "?")
 	key := String()
 
-	l := rv.Len()
 	out := make(map[string]interface{}, l)
-	keys := rv.MapKeys()
-	for i := 0; i != l; i++ {
-		k := keys[i]
-		newk, err := key.Coerce(k.Interface(), path)
+	var typed reflect.Value
+	if c.opts.Type != nil {
+		typed = reflect.MakeMapWithSize(c.opts.Type, l)
+	}
+	sources := make(map[string]string)
+	for _, k := range sortedKeys(rv) {
+		newk, err := key.Coerce(k.Interface(), keyPath(path, k.Interface()))
 		if err != nil {
 			return nil, err
 		}
-		vpath[len(vpath)-1] = fmt.Sprint(k.Interface())
+		ks := newk.(string)
+		if c.opts.NormalizeKey != nil {
+			ks = c.opts.NormalizeKey(ks)
+			if other, ok := sources[ks]; ok {
+				dups := []string{other, newk.(string)}
+				sort.Strings(dups)
+				return nil, fieldsError{path, dups, "duplicate keys"}
+			}
+			sources[ks] = newk.(string)
+		}
+		if err := checkKey(c.opts.KeyPattern, ks, keyPath(path, ks)); err != nil {
+			return nil, err
+		}
+		vpath[len(vpath)-1] = ks
 		newv, err := c.value.Coerce(rv.MapIndex(k).Interface(), vpath)
 		if err != nil {
 			return nil, err
 		}
-		out[newk.(string)] = newv
+		if !typed.IsValid() {
+			out[ks] = newv
+			continue
+		}
+		tv, err := assignableValue(c.opts.Type.Elem(), newv, vpath)
+		if err != nil {
+			return nil, secretError(c.value, err, vpath)
+		}
+		typed.SetMapIndex(reflect.ValueOf(ks).Convert(c.opts.Type.Key()), tv)
+	}
+	if typed.IsValid() {
+		return typed.Interface(), nil
+	}
+	return out, nil
+}
+
+// Uncoerce implements Encoder.
+func (c stringMapC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || !hasStrictStringKeys(rv) {
+		return nil, error_{"map[string]", errorValue(c, v), path}
+	}
+	vpath := append(path, ".", "?")
+	out := make(map[string]interface{}, rv.Len())
+	for _, k := range sortedKeys(rv) {
+		ks := keyString(k)
+		vpath[len(vpath)-1] = ks
+		newv, err := uncoerce(c.value, rv.MapIndex(k).Interface(), vpath)
+		if err != nil {
+			return nil, err
+		}
+		out[ks] = newv
 	}
 	return out, nil
 }
+
+func (c stringMapC) holdsSecret() bool {
+	return c.secret
+}
+
+func (c stringMapC) redact(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || !hasStrictStringKeys(rv) {
+		return v
+	}
+	out := make(map[string]interface{}, rv.Len())
+	for _, k := range rv.MapKeys() {
+		out[keyString(k)] = redact(c.value, rv.MapIndex(k).Interface())
+	}
+	return out
+}
+
+// sortedKeys returns the keys of the map held by rv, in the order
+// defined by lessValue.
+func sortedKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessValue(keys[i].Interface(), keys[j].Interface())
+	})
+	return keys
+}
+
+// keyPath returns the path used to report errors about the key k of
+// the map at path.
+func keyPath(path []string, k interface{}) []string {
+	kpath := make([]string, 0, len(path)+3)
+	kpath = append(kpath, path...)
+	return append(kpath, "{", fmt.Sprint(k), "}")
+}
+
+// checkKey returns an error if pattern is not nil and does not match
+// the string representation of key.
+func checkKey(pattern *regexp.Regexp, key interface{}, kpath []string) error {
+	if pattern == nil || pattern.MatchString(fmt.Sprint(key)) {
+		return nil
+	}
+	return error_{fmt.Sprintf("key matching %q", pattern), key, kpath}
+}
+
+func keysLabel(n int) string {
+	if n == 1 {
+		return "1 key"
+	}
+	return fmt.Sprintf("%d keys", n)
+}
+
+// Describe implements Descriptor.
+func (c mapC) Describe() Description {
+	d := Description{Kind: KindMap, Key: describePtr(c.key), Elem: describePtr(c.value)}
+	d.Label = fmt.Sprintf("map of %s to %s", d.Key.Label, d.Elem.Label)
+	d.MaxKeys = c.opts.MaxKeys
+	d.Constraints = append(keyConstraints(c.opts.KeyPattern), boundConstraints(d)...)
+	return d
+}
+
+// Describe implements Descriptor.
+func (c stringMapC) Describe() Description {
+	d := Description{Kind: KindStringMap, Elem: describePtr(c.value)}
+	d.Label = "map of string to " + d.Elem.Label
+	d.MaxKeys = c.opts.MaxKeys
+	d.Constraints = append(keyConstraints(c.opts.KeyPattern), boundConstraints(d)...)
+	return d
+}
+
+// keyConstraints describes the restrictions on the keys of a map.
+func keyConstraints(pattern *regexp.Regexp) []string {
+	if pattern == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("keys matching %q", pattern)}
+}
+
+// matchingKey returns k when its string representation matches the key
+// pattern, and otherwise a random string matching it that is accepted
+// by the key checker. It returns false if there is no such string.
+func (c mapC) matchingKey(k interface{}, r *rand.Rand, path []string) (interface{}, bool) {
+	if c.opts.KeyPattern == nil || c.opts.KeyPattern.MatchString(fmt.Sprint(k)) {
+		return k, true
+	}
+	s, ok := randomMatch(r, c.opts.KeyPattern)
+	if !ok {
+		return nil, false
+	}
+	if _, err := c.key.Coerce(s, keyPath(path, s)); err != nil {
+		return nil, false
+	}
+	return s, true
+}
+
+// matchingKey returns k, normalized, when it matches the key pattern,
+// and otherwise a random normalized key matching it. It returns false
+// if there is no such key.
+func (c stringMapC) matchingKey(k string, r *rand.Rand) (string, bool) {
+	if k, ok := c.normalizedMatch(k); ok {
+		return k, true
+	}
+	if c.opts.KeyPattern == nil {
+		return "", false
+	}
+	s, ok := randomMatch(r, c.opts.KeyPattern)
+	if !ok {
+		return "", false
+	}
+	return c.normalizedMatch(s)
+}
+
+func (c stringMapC) normalizedMatch(k string) (string, bool) {
+	if c.opts.NormalizeKey != nil {
+		k = c.opts.NormalizeKey(k)
+	}
+	return k, c.opts.KeyPattern == nil || c.opts.KeyPattern.MatchString(k)
+}
+
+func (c mapC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	out := make(map[interface{}]interface{})
+	if !opts.AllFields {
+		return out, nil
+	}
+	k, err := example(c.key, opts, path)
+	if err != nil {
+		return nil, err
+	}
+	// Keys matching the pattern are generated from a fixed seed, so
+	// that the example stays the same from one call to the next. The
+	// entry is left out if no matching key can be found.
+	k, ok := c.matchingKey(k, rand.New(rand.NewSource(1)), path)
+	if !ok {
+		return out, nil
+	}
+	v, err := example(c.value, opts, append(path, ".", fmt.Sprint(k)))
+	if err != nil {
+		return nil, err
+	}
+	out[k] = v
+	return out, nil
+}
+
+func (c stringMapC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	out := make(map[string]interface{})
+	if !opts.AllFields {
+		return out, nil
+	}
+	// See mapC.example.
+	k, ok := c.matchingKey("key", rand.New(rand.NewSource(1)))
+	if !ok {
+		return out, nil
+	}
+	v, err := example(c.value, opts, append(path, ".", k))
+	if err != nil {
+		return nil, err
+	}
+	out[k] = v
+	return out, nil
+}
+
+func (c mapC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	n := r.Intn(4)
+	if c.opts.MaxKeys > 0 && n > c.opts.MaxKeys {
+		n = c.opts.MaxKeys
+	}
+	out := make(map[interface{}]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := generate(c.key, r, path)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := c.matchingKey(k, r, path)
+		if !ok {
+			continue
+		}
+		v, err := generate(c.value, r, childPath(path, ".", fmt.Sprint(k)))
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (c mapC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	k, err := generate(c.key, r, path)
+	if err != nil {
+		return invalidLeaf(c, r, path)
+	}
+	k, ok := c.matchingKey(k, r, path)
+	if !ok {
+		return invalidLeaf(c, r, path)
+	}
+	v, vpath, ok := generateInvalid(c.value, r, childPath(path, ".", fmt.Sprint(k)))
+	if !ok {
+		return invalidLeaf(c, r, path)
+	}
+	return map[interface{}]interface{}{k: v}, vpath, true
+}
+
+func (c stringMapC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	n := r.Intn(4)
+	if c.opts.MaxKeys > 0 && n > c.opts.MaxKeys {
+		n = c.opts.MaxKeys
+	}
+	out := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, ok := c.matchingKey(randomWord(r), r)
+		if !ok {
+			continue
+		}
+		v, err := generate(c.value, r, childPath(path, ".", k))
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (c stringMapC) generateInvalid(r *rand.Rand, path []string) (interface{}, []string, bool) {
+	k, ok := c.matchingKey(randomWord(r), r)
+	if !ok {
+		return invalidLeaf(c, r, path)
+	}
+	v, vpath, ok := generateInvalid(c.value, r, childPath(path, ".", k))
+	if !ok {
+		return invalidLeaf(c, r, path)
+	}
+	return map[string]interface{}{k: v}, vpath, true
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+// Meta holds metadata about the values accepted by a checker, such as
+// what they mean. It is attached to a checker with WithMeta.
+type Meta struct {
+	// Title holds a short name for the value. It is included in the
+	// error reported when a field marked with Required is missing.
+	Title string
+
+	// Description holds a human readable description of the value.
+	Description string
+
+	// Examples holds example values accepted by the checker.
+	Examples []interface{}
+
+	// Secret marks the value as sensitive, such as a password or a
+	// token. Secret values are left out of error messages, and are
+	// masked by Redact.
+	Secret bool
+
+	// Deprecated marks the value as deprecated, and
+	// DeprecationMessage holds a message explaining what to use
+	// instead, which may be empty. When a FieldMap field is
+	// deprecated, a warning is reported when it is used.
+	Deprecated         bool
+	DeprecationMessage string
+
+	// Since holds the version the value was introduced in.
+	Since string
+}
+
+// merge returns m with its unset attributes taken from other.
+func (m Meta) merge(other Meta) Meta {
+	if m.Title == "" {
+		m.Title = other.Title
+	}
+	if m.Description == "" {
+		m.Description = other.Description
+	}
+	if m.Examples == nil {
+		m.Examples = other.Examples
+	}
+	if !m.Deprecated {
+		m.Deprecated = other.Deprecated
+		m.DeprecationMessage = other.DeprecationMessage
+	}
+	if m.Since == "" {
+		m.Since = other.Since
+	}
+	m.Secret = m.Secret || other.Secret
+	return m
+}
+
+// WithMeta returns a Checker that acts as checker, with meta attached
+// to it. The metadata is reported by Describe and included in the
+// documentation generated by Markdown and PlainText. When checker
+// already has metadata attached, the attributes set in meta take
+// precedence.
+func WithMeta(checker Checker, meta Meta) Checker {
+	return metaC{checker, meta}
+}
+
+// Documented returns a Checker that acts as checker, with doc attached
+// to it as the description of the value. It is a shorthand for
+// WithMeta(checker, Meta{Description: doc}).
+func Documented(checker Checker, doc string) Checker {
+	return WithMeta(checker, Meta{Description: doc})
+}
+
+type metaC struct {
+	checker Checker
+	meta    Meta
+}
+
+func (c metaC) unwrap() Checker {
+	return c.checker
+}
+
+func (c metaC) Coerce(v interface{}, path []string) (interface{}, error) {
+	newv, err := c.checker.Coerce(v, path)
+	if err != nil && c.meta.Secret {
+		return nil, redactError(err, Describe(c.checker).Label, path)
+	}
+	return newv, err
+}
+
+// Uncoerce implements Encoder.
+func (c metaC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	u, err := uncoerce(c.checker, v, path)
+	if err != nil && c.meta.Secret {
+		return nil, redactError(err, Describe(c.checker).Label, path)
+	}
+	return u, err
+}
+
+// Describe implements Descriptor.
+func (c metaC) Describe() Description {
+	d := Describe(c.checker)
+	d.Meta = c.meta.merge(d.Meta)
+	return d
+}
+
+// metaOf returns the metadata attached to checker.
+func metaOf(checker Checker) Meta {
+	var meta Meta
+	for {
+		if m, ok := checker.(metaC); ok {
+			meta = meta.merge(m.meta)
+		}
+		w, ok := checker.(wrapper)
+		if !ok {
+			return meta
+		}
+		checker = w.unwrap()
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestWithMeta(c *gc.C) {
+	sch := schema.WithMeta(schema.Documented(schema.String(), "The admin password."), schema.Meta{
+		Title:    "Password",
+		Examples: []interface{}{"hunter2"},
+		Secret:   true,
+		Since:    "2.9",
+	})
+	out, err := sch.Coerce("foo", aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "foo")
+
+	d := schema.Describe(sch)
+	c.Assert(d.Kind, gc.Equals, schema.KindString)
+	c.Assert(d.Meta, gc.DeepEquals, schema.Meta{
+		Title:       "Password",
+		Description: "The admin password.",
+		Examples:    []interface{}{"hunter2"},
+		Secret:      true,
+		Since:       "2.9",
+	})
+
+	// The outermost metadata takes precedence.
+	d = schema.Describe(schema.WithMeta(sch, schema.Meta{Title: "Secret"}))
+	c.Assert(d.Meta.Title, gc.Equals, "Secret")
+	c.Assert(d.Meta.Description, gc.Equals, "The admin password.")
+}
+
+func (s *S) TestWithMetaDeprecatedField(c *gc.C) {
+	var warnings []string
+	sch := schema.FieldMapWith(schema.Fields{
+		"units": schema.WithMeta(schema.Int(), schema.Meta{
+			Deprecated:         true,
+			DeprecationMessage: `use "scale" instead`,
+		}),
+		"scale": schema.Int(),
+	}, schema.Defaults{
+		"units": schema.Omit,
+		"scale": 1,
+	}, schema.FieldMapOptions{
+		Warn: func(w schema.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	})
+	out, err := sch.Coerce(map[string]interface{}{"units": 3}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"units": int64(3), "scale": int64(1)})
+	c.Assert(warnings, gc.DeepEquals, []string{`<path>.units: field is deprecated: use "scale" instead`})
+
+	fields := schema.Describe(sch).Fields
+	c.Assert(fields[1].Name, gc.Equals, "units")
+	c.Assert(fields[1].Deprecated, gc.Equals, true)
+	c.Assert(fields[1].DeprecationMessage, gc.Equals, `use "scale" instead`)
+}
+
+func (s *S) TestMarkdownMeta(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"password": schema.WithMeta(schema.String(), schema.Meta{
+			Title:       "Password",
+			Description: "The admin password.",
+			Secret:      true,
+		}),
+		"port": schema.WithMeta(schema.Int(), schema.Meta{
+			Examples: []interface{}{8080},
+			Since:    "2.9",
+		}),
+	}, schema.Defaults{
+		"password": "changeme",
+		"port":     80,
+	})
+	c.Assert(schema.Markdown(sch), gc.Equals, ""+
+		"| Field | Type | Required | Default | Allowed values | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| `password` | string | no |  |  | Password. The admin password. Secret. |\n"+
+		"| `port` | int | no | 80 |  | Examples: 8080. Since 2.9. |\n")
+}
+
+func (s *S) TestWithMetaTitleInErrors(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"password": schema.Required(schema.WithMeta(schema.String(), schema.Meta{Title: "Admin password"})),
+		"user":     schema.Required(schema.String()),
+	}, nil)
+	_, err := sch.Coerce(map[string]interface{}{"user": "admin"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.password: missing required field \(Admin password\)`)
+
+	_, err = sch.Coerce(map[string]interface{}{"password": "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.user: missing required field`)
+}
@@ -4,6 +4,7 @@
 package schema
 
 import (
+	"math/rand"
 	"reflect"
 	"strconv"
 )
@@ -197,3 +198,81 @@ func (c floatC) Coerce(v interface{}, path []string) (interface{}, error) {
 	var floatValue float64
 	return reflect.ValueOf(v).Convert( reflect.TypeOf(floatValue) ).Float() , nil
 }
+
+// Describe implements Descriptor.
+func (c boolC) Describe() Description {
+	return Description{Kind: KindBool, Label: "bool"}
+}
+
+// Describe implements Descriptor.
+func (c intC) Describe() Description {
+	return Description{Kind: KindInt, Label: "int"}
+}
+
+// Describe implements Descriptor.
+func (c uintC) Describe() Description {
+	return Description{Kind: KindUint, Label: "uint"}
+}
+
+// Describe implements Descriptor.
+func (c forceIntC) Describe() Description {
+	return Description{Kind: KindForceInt, Label: "number as int"}
+}
+
+// Describe implements Descriptor.
+func (c forceUintC) Describe() Description {
+	return Description{Kind: KindForceUint, Label: "number as uint"}
+}
+
+// Describe implements Descriptor.
+func (c floatC) Describe() Description {
+	return Description{Kind: KindFloat, Label: "float"}
+}
+
+func (c boolC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return false, nil
+}
+
+func (c intC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return 0, nil
+}
+
+func (c uintC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return 0, nil
+}
+
+func (c forceIntC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return 0, nil
+}
+
+func (c forceUintC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return 0, nil
+}
+
+func (c floatC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return 0.0, nil
+}
+
+func (c boolC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return r.Intn(2) == 1, nil
+}
+
+func (c intC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return r.Intn(2001) - 1000, nil
+}
+
+func (c uintC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return r.Intn(1000), nil
+}
+
+func (c forceIntC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return r.Intn(2001) - 1000, nil
+}
+
+func (c forceUintC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return r.Intn(1000), nil
+}
+
+func (c floatC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return float64(r.Intn(200001)-100000) / 100, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import "fmt"
+
+// Redacted is the value that secret values are replaced with by Redact,
+// and the text printed instead of them in error messages.
+const Redacted = "<redacted>"
+
+// Secret returns a Checker that acts as checker, but marks the values
+// it accepts as sensitive. Errors returned when processing a secret
+// value don't include the value, and Redact masks it. It is a shorthand
+// for WithMeta(checker, Meta{Secret: true}).
+func Secret(checker Checker) Checker {
+	return WithMeta(checker, Meta{Secret: true})
+}
+
+// Redact returns a copy of v, a value coerced by c, with every value
+// coerced by a secret checker replaced by Redacted, so that it can be
+// safely logged. Maps and lists holding secret values are returned as
+// map[string]interface{}, map[interface{}]interface{} and
+// []interface{} values. Parts of v that don't match the shape expected
+// by c are returned unchanged.
+func Redact(c Checker, v interface{}) interface{} {
+	return redact(c, v)
+}
+
+// redacter is implemented by checkers that may hold secret values.
+type redacter interface {
+	redact(v interface{}) interface{}
+}
+
+func redact(c Checker, v interface{}) interface{} {
+	if r, ok := c.(redacter); ok {
+		return r.redact(v)
+	}
+	if w, ok := c.(wrapper); ok {
+		return redact(w.unwrap(), v)
+	}
+	return v
+}
+
+func (c metaC) redact(v interface{}) interface{} {
+	if c.meta.Secret && v != nil {
+		return Redacted
+	}
+	return redact(c.checker, v)
+}
+
+// redacted is printed instead of a secret value in error messages.
+// When typ is not empty, it holds the type of a value that contains
+// secret values, which is reported along with the redacted marker.
+type redacted struct {
+	typ string
+}
+
+func (redacted) String() string {
+	return Redacted
+}
+
+func (redacted) GoString() string {
+	return Redacted
+}
+
+// redactError returns err with the value it reports replaced by
+// Redacted. Errors that don't report a value are returned unchanged,
+// and errors that may hold it in their message are replaced by one
+// expecting the value described by label at path.
+func redactError(err error, label string, path []string) error {
+	switch err := err.(type) {
+	case error_:
+		err.got = redacted{}
+		return err
+	case *UnknownKeyError:
+		e := *err
+		e.Value = redacted{}
+		return &e
+	case fieldsError:
+		return err
+	}
+	return error_{label, redacted{}, path}
+}
+
+// errorValue returns v for use as the value reported by an error from
+// c, or a placeholder holding only its type if c may hold secret
+// values, so that errors from containers don't expose the secrets they
+// hold.
+func errorValue(c Checker, v interface{}) interface{} {
+	if v != nil && holdsSecret(c) {
+		return redacted{fmt.Sprintf("%T", v)}
+	}
+	return v
+}
+
+// secretError returns err with the value it reports redacted if c may
+// hold secret values.
+func secretError(c Checker, err error, path []string) error {
+	if !holdsSecret(c) {
+		return err
+	}
+	return redactError(err, Describe(c).Label, path)
+}
+
+// secretHolder is implemented by checkers made of other checkers. They
+// record whether any of them is secret when they are created, so that
+// it is known without walking the whole checker tree.
+type secretHolder interface {
+	holdsSecret() bool
+}
+
+// holdsSecret reports whether c, or any of the checkers it is made of,
+// is marked as secret.
+func holdsSecret(c Checker) bool {
+	for {
+		if m, ok := c.(metaC); ok && m.meta.Secret {
+			return true
+		}
+		if h, ok := c.(secretHolder); ok {
+			return h.holdsSecret()
+		}
+		w, ok := c.(wrapper)
+		if !ok {
+			return false
+		}
+		c = w.unwrap()
+	}
+}
+
+// anySecret reports whether any of checkers holds secret values.
+func anySecret(checkers ...Checker) bool {
+	for _, c := range checkers {
+		if holdsSecret(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+func (s *S) TestSecretErrors(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"password": schema.Secret(schema.String()),
+		"pin":      schema.Secret(schema.ForceInt()),
+		"token":    schema.Secret(schema.StrictFieldMap(schema.Fields{"id": schema.Int()}, nil)),
+	}, schema.Defaults{
+		"pin":   schema.Omit,
+		"token": schema.Omit,
+	})
+
+	_, err := sch.Coerce(map[string]interface{}{"password": 1234}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.password: expected string, got <redacted>`)
+
+	_, err = sch.Coerce(map[string]interface{}{"password": "x", "pin": "12a4"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.pin: expected number, got <redacted>`)
+	c.Assert(err, gc.Not(gc.ErrorMatches), `.*12a4.*`)
+
+	_, err = sch.Coerce(map[string]interface{}{"password": "x", "token": map[string]interface{}{"id": "abc"}}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.token\.id: expected int, got <redacted>`)
+
+	_, err = sch.Coerce(map[string]interface{}{"password": "x", "token": map[string]interface{}{"key": "abc"}}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.token: unknown key "key" \(value <redacted>\)`)
+
+	// A mistyped key may hold the value of a secret field.
+	strict := schema.StrictFieldMap(schema.Fields{"password": schema.Secret(schema.String())}, nil)
+	_, err = strict.Coerce(map[string]interface{}{"pasword": "hunter2"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "pasword" \(value <redacted>\); did you mean "password"\?`)
+
+	_, err = schema.Uncoerce(schema.Secret(schema.URL()), "hunter2")
+	c.Assert(err, gc.ErrorMatches, `expected \*url\.URL, got <redacted>`)
+
+	// Errors that don't include the value are unchanged.
+	required := schema.FieldMap(schema.Fields{"password": schema.Required(schema.Secret(schema.String()))}, nil)
+	_, err = required.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.password: missing required field`)
+}
+
+func (s *S) TestSecretContainerErrors(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{"password": schema.Secret(schema.String())}, nil)
+	_, err := sch.Coerce(map[interface{}]interface{}{1: "hunter2", "password": "hunter2"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected map\[string\], got map\[interface \{\}\]interface \{\}\(<redacted>\)`)
+	c.Assert(err, gc.Not(gc.ErrorMatches), `.*hunter2.*`)
+
+	list := schema.ListWith(schema.Secret(schema.String()), schema.ListOptions{MinLen: 2})
+	_, err = list.Coerce([]interface{}{"hunter2"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with at least 2 elements, got \[\]interface \{\}\(<redacted>\)`)
+	c.Assert(err, gc.Not(gc.ErrorMatches), `.*hunter2.*`)
+
+	smap := schema.StringMapWith(schema.Secret(schema.String()), schema.StringMapOptions{MaxKeys: 1})
+	_, err = smap.Coerce(map[string]interface{}{"a": "hunter2", "b": "hunter3"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected map with at most 1 key, got map\[string\]interface \{\}\(<redacted>\)`)
+	c.Assert(err, gc.Not(gc.ErrorMatches), `.*hunter.*`)
+}
+
+func (s *S) TestRedact(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"name": schema.String(),
+		"auth": schema.FieldMap(schema.Fields{
+			"user":     schema.String(),
+			"password": schema.Secret(schema.String()),
+		}, nil),
+		"keys":    schema.List(schema.Secret(schema.String())),
+		"env":     schema.StringMap(schema.Secret(schema.String())),
+		"comment": schema.Secret(schema.Nullable(schema.String())),
+	}, schema.Defaults{
+		"keys":    schema.Omit,
+		"env":     schema.Omit,
+		"comment": nil,
+	})
+	out, err := sch.Coerce(map[string]interface{}{
+		"name": "app",
+		"auth": map[string]interface{}{
+			"user":     "admin",
+			"password": "hunter2",
+		},
+		"keys": []interface{}{"a", "b"},
+		"env":  map[string]interface{}{"TOKEN": "t"},
+	}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(schema.Redact(sch, out), gc.DeepEquals, map[string]interface{}{
+		"name": "app",
+		"auth": map[string]interface{}{
+			"user":     "admin",
+			"password": schema.Redacted,
+		},
+		"keys":    []interface{}{schema.Redacted, schema.Redacted},
+		"env":     map[string]interface{}{"TOKEN": schema.Redacted},
+		"comment": nil,
+	})
+
+	// The coerced value is left unchanged.
+	c.Assert(out.(map[string]interface{})["auth"], gc.DeepEquals, map[string]interface{}{
+		"user":     "admin",
+		"password": "hunter2",
+	})
+}
+
+func (s *S) TestRedactFieldMapSwitch(c *gc.C) {
+	sch := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"basic": schema.FieldMap(schema.Fields{
+			"type":     schema.Const("basic"),
+			"password": schema.Secret(schema.String()),
+		}, nil),
+		"token": schema.FieldMap(schema.Fields{
+			"type":  schema.Const("token"),
+			"token": schema.Secret(schema.String()),
+		}, nil),
+	}, nil)
+	out := schema.Redact(sch, map[string]interface{}{"type": "token", "token": "abc"})
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "token", "token": schema.Redacted})
+
+	// Values of an unexpected shape are returned unchanged.
+	c.Assert(schema.Redact(sch, "abc"), gc.Equals, "abc")
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"reflect"
+	"regexp"
 	"time"
 
 	gc "gopkg.in/check.v1"
@@ -442,6 +444,264 @@ func (s *S) TestList(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `<path>\[1\]: expected int, got bool\(true\)`)
 }
 
+func (s *S) TestListWithLength(c *gc.C) {
+	sch := schema.ListWith(schema.Int(), schema.ListOptions{MinLen: 1, MaxLen: 2})
+
+	out, err := sch.Coerce([]int{1, 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(2)})
+
+	_, err = sch.Coerce([]int{}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with at least 1 element, got \[\]int\(\[\]int\{\}\)`)
+
+	_, err = sch.Coerce([]int{1, 2, 3}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with at most 2 elements, got \[\]int\(\[\]int\{1, 2, 3\}\)`)
+}
+
+func (s *S) TestListWithUnique(c *gc.C) {
+	sch := schema.ListWith(schema.Int(), schema.ListOptions{Unique: true})
+
+	out, err := sch.Coerce([]interface{}{1, "2", 3}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(2), int64(3)})
+
+	// Uniqueness applies to coerced values.
+	_, err = sch.Coerce([]interface{}{1, "2", int8(2)}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\[2\]: duplicate of element 1`)
+
+	sch = schema.ListWith(schema.StringMap(schema.Any()), schema.ListOptions{
+		UniqueKey: func(elem interface{}) interface{} {
+			return elem.(map[string]interface{})["name"]
+		},
+	})
+	_, err = sch.Coerce([]interface{}{
+		map[string]interface{}{"name": "a", "port": 1},
+		map[string]interface{}{"name": "b", "port": 1},
+	}, aPath)
+	c.Assert(err, gc.IsNil)
+
+	_, err = sch.Coerce([]interface{}{
+		map[string]interface{}{"name": "a", "port": 1},
+		map[string]interface{}{"name": "a", "port": 2},
+	}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\[1\]: duplicate of element 0`)
+
+	// Values that cannot be map keys are supported.
+	sch = schema.ListWith(schema.List(schema.Int()), schema.ListOptions{Unique: true})
+	_, err = sch.Coerce([]interface{}{[]int{1}, []int{2}, []int{1}}, nil)
+	c.Assert(err, gc.ErrorMatches, `\[2\]: duplicate of element 0`)
+}
+
+func (s *S) TestListWithContains(c *gc.C) {
+	sch := schema.ListWith(schema.String(), schema.ListOptions{
+		Contains: schema.Const("default"),
+	})
+
+	_, err := sch.Coerce([]string{"a", "default"}, aPath)
+	c.Assert(err, gc.IsNil)
+
+	_, err = sch.Coerce([]string{"a", "b"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list containing a matching element, got \[\]string\(\[\]string\{"a", "b"\}\)`)
+}
+
+func (s *S) TestListWithArrays(c *gc.C) {
+	sch := schema.List(schema.Int())
+	_, err := sch.Coerce([2]int{1, 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list, got \[2\]int\(\[2\]int\{1, 2\}\)`)
+
+	sch = schema.ListWith(schema.Int(), schema.ListOptions{Arrays: true})
+	out, err := sch.Coerce([2]int{1, 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(2)})
+}
+
+func (s *S) TestListWithIterators(c *gc.C) {
+	seq := func(n int) func(yield func(int) bool) {
+		return func(yield func(int) bool) {
+			for i := 0; i < n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+	sch := schema.List(schema.Int())
+	_, err := sch.Coerce(seq(2), aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list, got func\(func\(int\) bool\)\(.*\)`)
+
+	sch = schema.ListWith(schema.Int(), schema.ListOptions{Iterators: true})
+	out, err := sch.Coerce(seq(3), aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(0), int64(1), int64(2)})
+
+	out, err = sch.Coerce(seq(0), aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{})
+
+	var nilSeq func(yield func(int) bool)
+	_, err = sch.Coerce(nilSeq, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list, got .*`)
+
+	_, err = sch.Coerce(func(int) bool { return true }, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list, got .*`)
+
+	// Iteration stops once there are too many elements.
+	produced := 0
+	endless := func(yield func(string) bool) {
+		for yield("1") {
+			produced++
+		}
+	}
+	sch = schema.ListWith(schema.Int(), schema.ListOptions{Iterators: true, MaxLen: 2})
+	_, err = sch.Coerce(endless, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with at most 2 elements, got .*`)
+	c.Assert(produced, gc.Equals, 2)
+}
+
+func (s *S) TestListWithSingle(c *gc.C) {
+	sch := schema.ListWith(schema.Int(), schema.ListOptions{Single: true})
+
+	out, err := sch.Coerce(80, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(80)})
+
+	out, err = sch.Coerce([]int{80, 443}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(80), int64(443)})
+
+	_, err = sch.Coerce(true, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\[0\]: expected int, got bool\(true\)`)
+
+	_, err = sch.Coerce(nil, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list, got nothing`)
+}
+
+func (s *S) TestListWithSeparator(c *gc.C) {
+	sch := schema.ListWith(schema.Int(), schema.ListOptions{Separator: ",", Single: true})
+
+	out, err := sch.Coerce("1, 2,3", aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(2), int64(3)})
+
+	out, err = sch.Coerce("", aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{})
+
+	out, err = sch.Coerce(4, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(4)})
+
+	_, err = sch.Coerce("1,a", aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\[1\]: expected int, got string\("a"\)`)
+}
+
+func (s *S) TestSet(c *gc.C) {
+	sch := schema.Set(schema.Int())
+
+	out, err := sch.Coerce([]interface{}{3, "1", 2, int8(3), 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(2), int64(3)})
+
+	out, err = sch.Coerce([]interface{}{3, true}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\[1\]: expected int, got bool\(true\)`)
+
+	sch = schema.Set(schema.Any())
+	out, err = sch.Coerce([]interface{}{"b", 2.5, true, "a", 1, nil, false, "b"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{nil, false, true, 1, 2.5, "a", "b"})
+}
+
+func (s *S) TestListWithType(c *gc.C) {
+	sch := schema.ListWith(schema.String(), schema.ListOptions{
+		Type: reflect.TypeOf([]string(nil)),
+	})
+	out, err := sch.Coerce([]interface{}{"a", "b"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []string{"a", "b"})
+
+	sch = schema.ListWith(schema.Int(), schema.ListOptions{
+		Type: reflect.TypeOf([]int(nil)),
+	})
+	out, err = sch.Coerce([]interface{}{1, 2}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\[0\]: expected int, got int64\(1\)`)
+
+	sch = schema.ListWith(schema.Nullable(schema.URL()), schema.ListOptions{
+		Type: reflect.TypeOf([]*url.URL(nil)),
+	})
+	out, err = sch.Coerce([]interface{}{"http://a", nil}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []*url.URL{{Scheme: "http", Host: "a"}, nil})
+
+	c.Assert(func() {
+		schema.ListWith(schema.Int(), schema.ListOptions{Type: reflect.TypeOf(0)})
+	}, gc.PanicMatches, `ListWith got non-slice output type int`)
+}
+
+func (s *S) TestTuple(c *gc.C) {
+	sch := schema.Tuple(schema.String(), schema.Int())
+	out, err := sch.Coerce([]interface{}{"localhost", "8080"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{"localhost", int64(8080)})
+
+	out, err = sch.Coerce([]interface{}{"localhost", true}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\[1\]: expected int, got bool\(true\)`)
+
+	out, err = sch.Coerce([]interface{}{"localhost"}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with 2 elements, got \[\]interface {}\(\[\]interface {}{"localhost"}\)`)
+
+	out, err = sch.Coerce([]interface{}{"localhost", 1, 2}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with 2 elements, got .*`)
+
+	out, err = sch.Coerce(nil, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "<path>: expected list, got nothing")
+}
+
+func (s *S) TestTupleWith(c *gc.C) {
+	sch := schema.TupleWith([]schema.Checker{
+		schema.Int(), schema.Int(), schema.Int(),
+	}, schema.TupleOptions{Optional: 1})
+
+	out, err := sch.Coerce([]int{1, 5}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(5)})
+
+	out, err = sch.Coerce([]int{1, 5, 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{int64(1), int64(5), int64(2)})
+
+	_, err = sch.Coerce([]int{1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with 2 to 3 elements, got \[\]int\(\[\]int\{1\}\)`)
+
+	// All the items may be optional.
+	sch = schema.TupleWith([]schema.Checker{schema.Int(), schema.Int()}, schema.TupleOptions{Optional: 2})
+	out, err = sch.Coerce([]int{}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{})
+
+	_, err = sch.Coerce([]int{1, 2, 3}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with 0 to 2 elements, got .*`)
+
+	sch = schema.TupleWith([]schema.Checker{schema.String()}, schema.TupleOptions{
+		Rest: schema.Int(),
+	})
+
+	out, err = sch.Coerce([]interface{}{"cmd", 1, 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, []interface{}{"cmd", int64(1), int64(2)})
+
+	_, err = sch.Coerce([]interface{}{"cmd", 1, "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\[2\]: expected int, got string\("x"\)`)
+
+	_, err = sch.Coerce([]interface{}{}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected list with at least 1 element, got .*`)
+}
+
 func (s *S) TestMap(c *gc.C) {
 	sch := schema.Map(schema.String(), schema.Int())
 	out, err := sch.Coerce(map[string]interface{}{"a": 1, "b": int8(2)}, aPath)
@@ -458,7 +718,7 @@ func (s *S) TestMap(c *gc.C) {
 
 	out, err = sch.Coerce(map[int]int{1: 1}, aPath)
 	c.Assert(out, gc.IsNil)
-	c.Assert(err, gc.ErrorMatches, "<path>: expected string, got int\\(1\\)")
+	c.Assert(err, gc.ErrorMatches, "<path>\\{1\\}: expected string, got int\\(1\\)")
 
 	out, err = sch.Coerce(map[string]bool{"a": true}, aPath)
 	c.Assert(out, gc.IsNil)
@@ -491,7 +751,7 @@ func (s *S) TestStringMap(c *gc.C) {
 
 	out, err = sch.Coerce(map[int]int{1: 1}, aPath)
 	c.Assert(out, gc.IsNil)
-	c.Assert(err, gc.ErrorMatches, "<path>: expected string, got int\\(1\\)")
+	c.Assert(err, gc.ErrorMatches, "<path>\\{1\\}: expected string, got int\\(1\\)")
 
 	out, err = sch.Coerce(map[string]bool{"a": true}, aPath)
 	c.Assert(out, gc.IsNil)
@@ -503,6 +763,104 @@ func (s *S) TestStringMap(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `a: expected int, got bool\(true\)`)
 }
 
+func (s *S) TestMapKeyErrors(c *gc.C) {
+	sch := schema.Map(schema.String(), schema.Int())
+
+	// Keys are processed in order, so the first failing one is
+	// always reported.
+	for i := 0; i < 10; i++ {
+		_, err := sch.Coerce(map[interface{}]interface{}{"a": 1, 3: 1, 2: 1, "b": 1}, []string{"labels"})
+		c.Assert(err, gc.ErrorMatches, `labels\{2\}: expected string, got int\(2\)`)
+
+		_, err = sch.Coerce(map[string]interface{}{"d": "x", "c": "x", "e": "x"}, []string{"labels"})
+		c.Assert(err, gc.ErrorMatches, `labels\.c: expected int, got string\("x"\)`)
+	}
+
+	ssch := schema.StringMap(schema.Int())
+	_, err := ssch.Coerce(map[interface{}]interface{}{"a": 1, 3: 1}, []string{"labels"})
+	c.Assert(err, gc.ErrorMatches, `labels\{3\}: expected string, got int\(3\)`)
+}
+
+func (s *S) TestMapWithKeyConstraints(c *gc.C) {
+	sch := schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{
+		KeyPattern: regexp.MustCompile(`^[a-z]+$`),
+		MaxKeys:    2,
+	})
+
+	out, err := sch.Coerce(map[string]int{"a": 1, "b": 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[interface{}]interface{}{"a": int64(1), "b": int64(2)})
+
+	_, err = sch.Coerce(map[string]int{"a": 1, "B": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\{B\}: expected key matching "\^\[a-z\]\+\$", got string\("B"\)`)
+
+	_, err = sch.Coerce(map[string]int{"a": 1, "b": 2, "c": 3}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected map with at most 2 keys, got .*`)
+}
+
+func (s *S) TestStringMapWithKeyConstraints(c *gc.C) {
+	sch := schema.StringMapWith(schema.Int(), schema.StringMapOptions{
+		NormalizeKey: schema.FoldKeyCase,
+		KeyPattern:   regexp.MustCompile(`^[a-z]+$`),
+		MaxKeys:      1,
+	})
+
+	out, err := sch.Coerce(map[string]int{"A": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": int64(1)})
+
+	_, err = sch.Coerce(map[string]int{"a-b": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\{a-b\}: expected key matching .*, got string\("a-b"\)`)
+
+	_, err = sch.Coerce(map[string]int{"a": 1, "b": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected map with at most 1 key, got .*`)
+}
+
+func (s *S) TestMapWithType(c *gc.C) {
+	sch := schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{
+		Type: reflect.TypeOf(map[string]int64(nil)),
+	})
+	out, err := sch.Coerce(map[interface{}]interface{}{"a": 1, "b": "2"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]int64{"a": 1, "b": 2})
+
+	sch = schema.MapWith(schema.Int(), schema.Int(), schema.MapOptions{
+		Type: reflect.TypeOf(map[string]int64(nil)),
+	})
+	_, err = sch.Coerce(map[int]int{1: 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\{1\}: expected string, got int64\(1\)`)
+
+	sch = schema.MapWith(schema.String(), schema.Int(), schema.MapOptions{
+		Type: reflect.TypeOf(map[string]string(nil)),
+	})
+	_, err = sch.Coerce(map[string]int{"a": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a: expected string, got int64\(1\)`)
+
+	c.Assert(func() {
+		schema.MapWith(schema.Int(), schema.Int(), schema.MapOptions{Type: reflect.TypeOf("")})
+	}, gc.PanicMatches, `MapWith got non-map output type string`)
+}
+
+func (s *S) TestStringMapWithType(c *gc.C) {
+	type label string
+	sch := schema.StringMapWith(schema.Int(), schema.StringMapOptions{
+		Type: reflect.TypeOf(map[label]int64(nil)),
+	})
+	out, err := sch.Coerce(map[string]interface{}{"a": 1, "b": "2"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[label]int64{"a": 1, "b": 2})
+
+	sch = schema.StringMapWith(schema.Int(), schema.StringMapOptions{
+		Type: reflect.TypeOf(map[string]float64(nil)),
+	})
+	_, err = sch.Coerce(map[string]interface{}{"a": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a: expected float64, got int64\(1\)`)
+
+	c.Assert(func() {
+		schema.StringMapWith(schema.Int(), schema.StringMapOptions{Type: reflect.TypeOf(map[int]int(nil))})
+	}, gc.PanicMatches, `StringMapWith got non-string-keyed map output type map\[int\]int`)
+}
+
 func assertFieldMap(c *gc.C, sch schema.Checker) {
 	out, err := sch.Coerce(map[string]interface{}{"a": "A", "b": "B"}, aPath)
 
@@ -619,6 +977,434 @@ func (s *S) TestStrictFieldMap(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown key "d" \(value "D"\)`)
 }
 
+func (s *S) TestFieldMapRequired(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"a": schema.Required(schema.Int()),
+		"b": schema.Int(),
+	}, schema.Defaults{
+		"a": 1,
+		"b": 2,
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"a": 3}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": int64(3), "b": int64(2)})
+
+	out, err = sch.Coerce(map[string]interface{}{"b": 3}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a: missing required field`)
+
+	// An explicit nil is not a missing field.
+	out, err = sch.Coerce(map[string]interface{}{"a": nil}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a: expected int, got nothing`)
+}
+
+func (s *S) TestFieldMapOptional(c *gc.C) {
+	sch := schema.FieldMap(schema.Fields{
+		"a": schema.Optional(schema.Int()),
+		"b": schema.Optional(schema.Int()),
+	}, schema.Defaults{
+		"b": 2,
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"b": int64(2)})
+
+	out, err = sch.Coerce(map[string]interface{}{"a": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": int64(1), "b": int64(2)})
+}
+
+func (s *S) TestNullable(c *gc.C) {
+	sch := schema.Nullable(schema.Int())
+
+	out, err := sch.Coerce(nil, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.IsNil)
+
+	out, err = sch.Coerce("1", aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, int64(1))
+
+	out, err = sch.Coerce(true, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected int, got bool\(true\)`)
+}
+
+func (s *S) TestFieldMapNullable(c *gc.C) {
+	fields := schema.Fields{
+		"a": schema.Nullable(schema.Int()),
+		"b": schema.Required(schema.Nullable(schema.Int())),
+	}
+	defaults := schema.Defaults{
+		"a": 1,
+	}
+	sch := schema.FieldMap(fields, defaults)
+
+	out, err := sch.Coerce(map[string]interface{}{"b": 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": int64(1), "b": int64(2)})
+
+	// Explicit nulls are not replaced by defaults, and are omitted.
+	out, err = sch.Coerce(map[string]interface{}{"a": nil, "b": nil}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{})
+
+	out, err = sch.Coerce(map[string]interface{}{"a": nil}, aPath)
+	c.Assert(out, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `<path>\.b: missing required field`)
+
+	sch = schema.FieldMapWith(fields, defaults, schema.FieldMapOptions{
+		PreserveNulls: true,
+	})
+	out, err = sch.Coerce(map[string]interface{}{"a": nil, "b": nil}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": nil, "b": nil})
+}
+
+func (s *S) TestStrictFieldMapSuggestions(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"replicas": schema.Int(),
+		"region":   schema.String(),
+		"regions":  schema.List(schema.String()),
+	}, schema.Defaults{
+		"replicas": 1,
+		"region":   schema.Omit,
+		"regions":  schema.Omit,
+	}, schema.FieldMapOptions{
+		Strict: true,
+		Aliases: map[string]string{
+			"zones": "regions",
+		},
+	})
+
+	_, err := sch.Coerce(map[string]interface{}{"replcas": 3}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "replcas" \(value 3\); did you mean "replicas"\?`)
+	uerr, ok := err.(*schema.UnknownKeyError)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(uerr.Path, gc.DeepEquals, aPath)
+	c.Assert(uerr.Key, gc.Equals, "replcas")
+	c.Assert(uerr.Value, gc.Equals, 3)
+	c.Assert(uerr.Suggestions, gc.DeepEquals, []string{"replicas"})
+
+	_, err = sch.Coerce(map[string]interface{}{"regionz": "r"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "regionz" \(value "r"\); did you mean one of "region", "regions"\?`)
+
+	_, err = sch.Coerce(map[string]interface{}{"zone": "r"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "zone" \(value "r"\); did you mean "zones"\?`)
+
+	_, err = sch.Coerce(map[string]interface{}{"name": "r"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "name" \(value "r"\)`)
+	c.Assert(err.(*schema.UnknownKeyError).Suggestions, gc.HasLen, 0)
+}
+
+func (s *S) TestFieldMapAliases(c *gc.C) {
+	var warnings []string
+	sch := schema.FieldMapWith(schema.Fields{
+		"max-units": schema.Int(),
+	}, schema.Defaults{
+		"max-units": 1,
+	}, schema.FieldMapOptions{
+		Strict: true,
+		Aliases: map[string]string{
+			"maxunits":  "max-units",
+			"max_units": "max-units",
+		},
+		Deprecated: map[string]string{
+			"maxunits": `use "max-units" instead`,
+		},
+		Renamed: map[string]string{
+			"units": "max-units",
+		},
+		Warn: func(w schema.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"max_units": 3}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"max-units": int64(3)})
+	c.Assert(warnings, gc.HasLen, 0)
+
+	out, err = sch.Coerce(map[string]interface{}{"maxunits": 4}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"max-units": int64(4)})
+	c.Assert(warnings, gc.DeepEquals, []string{`<path>.maxunits: field is deprecated: use "max-units" instead`})
+
+	// Errors are reported against the field name.
+	_, err = sch.Coerce(map[string]interface{}{"max_units": true}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.max-units: expected int, got bool\(true\)`)
+
+	_, err = sch.Coerce(map[string]interface{}{"max-units": 1, "max_units": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.max-units, <path>\.max_units: only one may be specified`)
+
+	_, err = sch.Coerce(map[string]interface{}{"units": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: field "units" was renamed to "max-units"`)
+
+	_, err = sch.Coerce(map[interface{}]interface{}{"unit": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "unit" \(value 1\)`)
+
+	// Keys are processed in order, so the same error is reported
+	// every time.
+	for i := 0; i < 20; i++ {
+		_, err = sch.Coerce(map[string]interface{}{"max-units": 1, "max_units": 2, "unit": 3}, aPath)
+		c.Assert(err, gc.ErrorMatches, `<path>\.max-units, <path>\.max_units: only one may be specified`)
+	}
+
+	// Deprecation warnings are reported in order.
+	warnings = nil
+	sch = schema.FieldMapWith(schema.Fields{"a": schema.Int(), "b": schema.Int(), "c": schema.Int()}, nil, schema.FieldMapOptions{
+		Deprecated: map[string]string{"a": "", "b": "", "c": ""},
+		Warn: func(w schema.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	})
+	for i := 0; i < 20; i++ {
+		warnings = nil
+		_, err = sch.Coerce(map[string]interface{}{"c": 1, "a": 2, "b": 3}, aPath)
+		c.Assert(err, gc.IsNil)
+		c.Assert(warnings, gc.DeepEquals, []string{
+			"<path>.a: field is deprecated",
+			"<path>.b: field is deprecated",
+			"<path>.c: field is deprecated",
+		})
+	}
+
+	c.Assert(func() {
+		schema.FieldMapWith(schema.Fields{"max-units": schema.Int()}, nil, schema.FieldMapOptions{
+			Aliases: map[string]string{"units": "max-unit"},
+		})
+	}, gc.PanicMatches, `FieldMapWith got alias "units" for unknown field "max-unit"`)
+}
+
+func (s *S) TestFieldMapRenamedLenient(c *gc.C) {
+	var warnings []schema.Warning
+	sch := schema.FieldMapWith(schema.Fields{
+		"max-units": schema.Int(),
+	}, schema.Defaults{
+		"max-units": 1,
+	}, schema.FieldMapOptions{
+		Renamed: map[string]string{
+			"units": "max-units",
+		},
+		Deprecated: map[string]string{
+			"max-units": "",
+		},
+		Warn: func(w schema.Warning) {
+			warnings = append(warnings, w)
+		},
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"units": 3}, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"max-units": int64(1)})
+	c.Assert(warnings, gc.DeepEquals, []schema.Warning{{
+		Path:    []string{".", "units"},
+		Message: `field was renamed to "max-units"`,
+	}})
+
+	warnings = nil
+	_, err = sch.Coerce(map[string]interface{}{"max-units": 3}, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(warnings, gc.HasLen, 1)
+	c.Assert(warnings[0].String(), gc.Equals, "max-units: field is deprecated")
+}
+
+func (s *S) TestFieldMapNormalizeKey(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"max-units": schema.Int(),
+		"name":      schema.String(),
+	}, schema.Defaults{
+		"max-units": 1,
+		"name":      schema.Omit,
+	}, schema.FieldMapOptions{
+		Strict:       true,
+		NormalizeKey: schema.NormalizeKeys(schema.FoldKeyCase, schema.FoldKeySeparators),
+		Renamed: map[string]string{
+			"units": "max-units",
+		},
+	})
+
+	for _, key := range []string{"max-units", "max_units", "MaxUnits", "MAX-UNITS"} {
+		out, err := sch.Coerce(map[string]interface{}{key: 3, "Name": "foo"}, aPath)
+		c.Assert(err, gc.IsNil)
+		c.Assert(out, gc.DeepEquals, map[string]interface{}{"max-units": int64(3), "name": "foo"})
+	}
+
+	_, err := sch.Coerce(map[string]interface{}{"MaxUnits": "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.max-units: expected int, got string\("x"\)`)
+
+	_, err = sch.Coerce(map[string]interface{}{"max_units": 1, "MaxUnits": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.MaxUnits, <path>\.max_units: duplicate keys`)
+
+	_, err = sch.Coerce(map[string]interface{}{"Units": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: field "Units" was renamed to "max-units"`)
+
+	_, err = sch.Coerce(map[string]interface{}{"Nmae": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "Nmae" \(value 2\); did you mean "name"\?`)
+}
+
+func (s *S) TestFieldMapNormalizeKeyCollision(c *gc.C) {
+	c.Assert(func() {
+		schema.FieldMapWith(schema.Fields{
+			"max-units": schema.Int(),
+			"max_units": schema.String(),
+		}, nil, schema.FieldMapOptions{NormalizeKey: schema.FoldKeySeparators})
+	}, gc.PanicMatches, `FieldMapWith got keys "max-units" and "max_units" that both normalize to "maxunits"`)
+
+	c.Assert(func() {
+		schema.FieldMapWith(schema.Fields{
+			"name": schema.String(),
+		}, nil, schema.FieldMapOptions{
+			NormalizeKey: schema.FoldKeyCase,
+			Aliases:      map[string]string{"Name": "name"},
+		})
+	}, gc.PanicMatches, `FieldMapWith got keys "Name" and "name" that both normalize to "name"`)
+
+	_, err := schema.NewFieldMapWith(schema.Fields{
+		"max-units": schema.Int(),
+		"max_units": schema.String(),
+	}, nil, schema.FieldMapOptions{NormalizeKey: schema.FoldKeySeparators})
+	c.Assert(err, gc.ErrorMatches, `FieldMapWith got keys "max-units" and "max_units" that both normalize to "maxunits"`)
+
+	_, err = schema.NewFieldMapWith(schema.Fields{"max-units": schema.Int()}, nil, schema.FieldMapOptions{
+		Aliases: map[string]string{"units": "max-unit"},
+	})
+	c.Assert(err, gc.ErrorMatches, `FieldMapWith got alias "units" for unknown field "max-unit"`)
+
+	sch, err := schema.NewFieldMapWith(schema.Fields{"max-units": schema.Int()}, nil, schema.FieldMapOptions{
+		NormalizeKey: schema.FoldKeySeparators,
+	})
+	c.Assert(err, gc.IsNil)
+	out, err := sch.Coerce(map[string]interface{}{"max_units": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"max-units": int64(1)})
+}
+
+func (s *S) TestStringMapNormalizeKey(c *gc.C) {
+	sch := schema.StringMapWith(schema.Int(), schema.StringMapOptions{
+		NormalizeKey: schema.FoldKeyCase,
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"A": 1, "b": 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"a": int64(1), "b": int64(2)})
+
+	_, err = sch.Coerce(map[string]interface{}{"A": true}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.a: expected int, got bool\(true\)`)
+
+	_, err = sch.Coerce(map[string]interface{}{"A": 1, "a": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.A, <path>\.a: duplicate keys`)
+}
+
+func (s *S) TestFieldMapExtra(c *gc.C) {
+	sch := schema.FieldMapWith(schema.Fields{
+		"name": schema.String(),
+	}, nil, schema.FieldMapOptions{
+		Strict: true,
+		Extra:  schema.String(),
+	})
+
+	out, err := sch.Coerce(map[string]interface{}{"name": "n", "team": "t", "tier": "1"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"name": "n", "team": "t", "tier": "1"})
+
+	_, err = sch.Coerce(map[string]interface{}{"name": "n", "team": "t", "tier": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.tier: expected string, got int\(1\)`)
+}
+
+func (s *S) TestFieldMapExtraKeys(c *gc.C) {
+	fields := schema.Fields{
+		"name": schema.String(),
+	}
+	opts := schema.FieldMapOptions{
+		Extra:     schema.Int(),
+		ExtraKeys: regexp.MustCompile(`^x-`),
+	}
+	sch := schema.FieldMapWith(fields, nil, opts)
+
+	out, err := sch.Coerce(map[string]interface{}{"name": "n", "x-weight": "2", "other": true}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"name": "n", "x-weight": int64(2)})
+
+	_, err = sch.Coerce(map[string]interface{}{"name": "n", "x-weight": true}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.x-weight: expected int, got bool\(true\)`)
+
+	opts.Strict = true
+	sch = schema.FieldMapWith(fields, nil, opts)
+	_, err = sch.Coerce(map[string]interface{}{"name": "n", "x-weight": 2, "other": true}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "other" \(value true\)`)
+}
+
+func (s *S) TestFieldMapKeepUnknown(c *gc.C) {
+	fields := schema.Fields{
+		"name": schema.String(),
+		"size": schema.Int(),
+	}
+	opts := schema.FieldMapOptions{
+		KeepUnknown: true,
+		Renamed: map[string]string{
+			"units": "size",
+		},
+	}
+	sch := schema.FieldMapWith(fields, nil, opts)
+
+	in := map[string]interface{}{
+		"name":  "n",
+		"size":  "3",
+		"units": 2,
+		"annotations": map[string]interface{}{
+			"other-tool": "x",
+		},
+	}
+	out, err := sch.Coerce(in, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"name": "n",
+		"size": int64(3),
+		"annotations": map[string]interface{}{
+			"other-tool": "x",
+		},
+	})
+
+	opts.UnknownField = "extras"
+	sch = schema.FieldMapWith(fields, nil, opts)
+	out, err = sch.Coerce(in, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"name": "n",
+		"size": int64(3),
+		"extras": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				"other-tool": "x",
+			},
+		},
+	})
+
+	out, err = sch.Coerce(map[string]interface{}{"name": "n", "size": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"name": "n", "size": int64(1)})
+
+	// A key named as the field holding unknown keys is rejected.
+	_, err = sch.Coerce(map[string]interface{}{"name": "n", "extras": map[string]interface{}{"a": 1}}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.extras: key is reserved for unknown keys`)
+
+	// Strict mode still rejects unknown keys.
+	opts.Strict = true
+	sch = schema.FieldMapWith(fields, nil, opts)
+	_, err = sch.Coerce(map[string]interface{}{"name": "n", "size": 1, "other": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "other" \(value 1\)`)
+
+	opts.UnknownField = "name"
+	c.Assert(func() {
+		schema.FieldMapWith(fields, nil, opts)
+	}, gc.PanicMatches, `FieldMapWith got UnknownField "name" that is the name of a field`)
+	_, err = schema.NewFieldMapWith(fields, nil, opts)
+	c.Assert(err, gc.ErrorMatches, `FieldMapWith got UnknownField "name" that is the name of a field`)
+}
+
 func (s *S) TestSchemaMap(c *gc.C) {
 	fields1 := schema.FieldMap(schema.Fields{
 		"type": schema.Const(1),
@@ -640,11 +1426,11 @@ func (s *S) TestSchemaMap(c *gc.C) {
 
 	out, err = sch.Coerce(map[string]int{}, aPath)
 	c.Assert(out, gc.IsNil)
-	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected supported selector, got nothing`)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected supported selector \(1, 3\), got nothing`)
 
 	out, err = sch.Coerce(map[string]int{"type": 2}, aPath)
 	c.Assert(out, gc.IsNil)
-	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected supported selector, got int\(2\)`)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected supported selector \(1, 3\), got int\(2\)`)
 
 	out, err = sch.Coerce(map[string]int{"type": 3, "b": 5}, aPath)
 	c.Assert(out, gc.IsNil)
@@ -661,7 +1447,146 @@ func (s *S) TestSchemaMap(c *gc.C) {
 	// First path entry shouldn't have dots in an error message.
 	out, err = sch.Coerce(map[string]int{"a": 1}, nil)
 	c.Assert(out, gc.IsNil)
-	c.Assert(err, gc.ErrorMatches, `type: expected supported selector, got nothing`)
+	c.Assert(err, gc.ErrorMatches, `type: expected supported selector \(1, 3\), got nothing`)
+}
+
+func (s *S) TestSchemaMapWrapped(c *gc.C) {
+	fields1 := schema.Nullable(schema.StrictFieldMap(schema.Fields{
+		"type": schema.Const("one"),
+		"a":    schema.Int(),
+	}, nil))
+	fields2 := schema.FieldMap(schema.Fields{
+		"type": schema.OneOf(schema.Const("two"), schema.Const("deux")),
+	}, nil)
+	sch, err := schema.NewFieldMapSet("type", []schema.Checker{fields1, fields2})
+	c.Assert(err, gc.IsNil)
+
+	out, err := sch.Coerce(map[string]interface{}{"type": "one", "a": 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "one", "a": int64(2)})
+
+	// The error from the selected branch is reported.
+	_, err = sch.Coerce(map[string]interface{}{"type": "one", "b": 2}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: unknown key "b" \(value 2\)`)
+
+	// Selector values are only listed when known.
+	_, err = sch.Coerce(map[string]interface{}{"type": "three"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected supported selector, got string\("three"\)`)
+}
+
+func (s *S) TestFieldMapSetFallback(c *gc.C) {
+	sch := schema.FieldMapSetWith("type", []schema.Checker{
+		schema.FieldMap(schema.Fields{"type": schema.Const("a"), "a": schema.Int()}, nil),
+	}, schema.StringMap(schema.String()))
+
+	out, err := sch.Coerce(map[string]interface{}{"type": "a", "a": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "a", "a": int64(1)})
+
+	out, err = sch.Coerce(map[string]interface{}{"type": "b", "b": "x"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "b", "b": "x"})
+
+	out, err = sch.Coerce(map[string]interface{}{"b": "x"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"b": "x"})
+
+	_, err = sch.Coerce(map[string]interface{}{"b": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.b: expected string, got int\(1\)`)
+
+	d := schema.Describe(sch)
+	c.Assert(d.Branches, gc.HasLen, 2)
+	c.Assert(d.Branches[1].HasValue, gc.Equals, false)
+	c.Assert(d.Branches[1].Checker.Kind, gc.Equals, schema.KindStringMap)
+
+	_, err = schema.NewFieldMapSetWith("type", []schema.Checker{schema.Int()}, schema.Any())
+	c.Assert(err, gc.ErrorMatches, `FieldMapSet got a non-FieldMap checker at index 0`)
+
+	// Maps with keys that can't be strings don't hold a selector.
+	_, err = schema.FieldMapSet("type", []schema.Checker{
+		schema.FieldMap(schema.Fields{"type": schema.Const("a")}, nil),
+	}).Coerce(map[int]interface{}{1: "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected supported selector \("a"\), got nothing`)
+}
+
+func (s *S) TestNewFieldMapSetErrors(c *gc.C) {
+	fmap := schema.FieldMap(schema.Fields{"type": schema.Const(1)}, nil)
+
+	_, err := schema.NewFieldMapSet("type", []schema.Checker{fmap, schema.Int()})
+	c.Assert(err, gc.ErrorMatches, `FieldMapSet got a non-FieldMap checker at index 1`)
+
+	_, err = schema.NewFieldMapSet("kind", []schema.Checker{fmap})
+	c.Assert(err, gc.ErrorMatches, `FieldMapSet has a FieldMap with a missing selector "kind" at index 0`)
+
+	c.Assert(func() {
+		schema.FieldMapSet("kind", []schema.Checker{fmap})
+	}, gc.PanicMatches, `FieldMapSet has a FieldMap with a missing selector "kind" at index 0`)
+}
+
+func (s *S) TestFieldMapSwitch(c *gc.C) {
+	sch := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"file": schema.StrictFieldMap(schema.Fields{
+			"type": schema.String(),
+			"path": schema.String(),
+		}, nil),
+		"url": schema.StrictFieldMap(schema.Fields{
+			"type": schema.String(),
+			"url":  schema.URL(),
+		}, nil),
+	}, nil)
+
+	out, err := sch.Coerce(map[string]interface{}{"type": "file", "path": "/tmp"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "file", "path": "/tmp"})
+
+	_, err = sch.Coerce(map[string]interface{}{"type": "file", "path": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.path: expected string, got int\(1\)`)
+
+	_, err = sch.Coerce(map[string]interface{}{"type": "ftp"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected one of "file", "url", got string\("ftp"\)`)
+
+	_, err = sch.Coerce(map[string]interface{}{}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected one of "file", "url", got nothing`)
+
+	_, err = sch.Coerce(map[string]interface{}{"type": []string{"file"}}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected one of "file", "url", got \[\]string\(\[\]string{"file"}\)`)
+
+	_, err = sch.Coerce(42, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected map, got int\(42\)`)
+
+	// Maps with keys that can't be strings don't hold a selector.
+	_, err = sch.Coerce(map[int]interface{}{1: "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.type: expected one of "file", "url", got nothing`)
+}
+
+func (s *S) TestFieldMapSwitchNumericSelector(c *gc.C) {
+	sch := schema.FieldMapSwitch("version", map[interface{}]schema.Checker{
+		1: schema.FieldMap(schema.Fields{"version": schema.Any(), "name": schema.String()}, nil),
+		2: schema.FieldMap(schema.Fields{"version": schema.Any(), "title": schema.String()}, nil),
+	}, nil)
+
+	for _, version := range []interface{}{1, int64(1), uint8(1), float64(1)} {
+		out, err := sch.Coerce(map[string]interface{}{"version": version, "name": "n"}, aPath)
+		c.Assert(err, gc.IsNil)
+		c.Assert(out, gc.DeepEquals, map[string]interface{}{"version": version, "name": "n"})
+	}
+
+	_, err := sch.Coerce(map[string]interface{}{"version": 1.5}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.version: expected one of 1, 2, got float64\(1\.5\)`)
+}
+
+func (s *S) TestFieldMapSwitchFallback(c *gc.C) {
+	sch := schema.FieldMapSwitch("type", map[interface{}]schema.Checker{
+		"file": schema.FieldMap(schema.Fields{"path": schema.String()}, nil),
+	}, schema.StringMap(schema.Any()))
+
+	out, err := sch.Coerce(map[string]interface{}{"type": "file", "path": "/tmp"}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"path": "/tmp"})
+
+	out, err = sch.Coerce(map[string]interface{}{"type": "other", "x": 1}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"type": "other", "x": 1})
 }
 
 func (s *S) TestUUID(c *gc.C) {
@@ -0,0 +1,176 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package schematest provides helpers for testing schema checkers with
+// tables of inputs and expected results. The helpers report failures
+// through the TestingT interface, which is implemented by both
+// *testing.T and gocheck's *check.C.
+package schematest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/schema"
+)
+
+// TestingT is the interface used to report test failures. It is
+// implemented by *testing.T, *testing.B and gocheck's *check.C.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// helper marks the caller as a test helper when t supports it, so that
+// failures are reported at the line calling into this package.
+func helper(t TestingT) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+}
+
+// Case describes a value given to a checker and the expected result.
+//
+// When none of Error, Path and Expected are set, the value must be
+// accepted and coerced to Output. Otherwise it must be rejected, with an error
+// satisfying all the expectations that are set.
+type Case struct {
+	// About describes the case in failure messages.
+	About string
+
+	// Input holds the value given to the checker.
+	Input interface{}
+
+	// Output holds the expected coerced value.
+	Output interface{}
+
+	// Error holds a regular expression that must match the whole
+	// error message.
+	Error string
+
+	// Path holds the path the error must be reported at, formatted as
+	// in error messages, such as "units[2].name".
+	Path string
+
+	// Expected holds what the checker must report it expected at
+	// Path, such as "int" for the error "a: expected int, got
+	// string("x")".
+	Expected string
+}
+
+func (c Case) fails() bool {
+	return c.Error != "" || c.Path != "" || c.Expected != ""
+}
+
+// Run coerces the input of each case with checker, and reports a
+// failure for each case whose result does not match the expectations.
+func Run(t TestingT, checker schema.Checker, cases []Case) {
+	helper(t)
+	for i, test := range cases {
+		if msg := check(checker, test); msg != "" {
+			t.Errorf("case %d%s: %s", i, about(test.About), msg)
+		}
+	}
+}
+
+func check(checker schema.Checker, test Case) string {
+	out, err := checker.Coerce(test.Input, nil)
+	if !test.fails() {
+		if err != nil {
+			return fmt.Sprintf("unexpected error coercing %#v: %v", test.Input, err)
+		}
+		if !reflect.DeepEqual(out, test.Output) {
+			return fmt.Sprintf("coercing %#v gives %#v, expected %#v", test.Input, out, test.Output)
+		}
+		return ""
+	}
+	if err == nil {
+		return fmt.Sprintf("coercing %#v gives %#v, expected an error", test.Input, out)
+	}
+	if test.Error != "" {
+		re, rerr := regexp.Compile("^(?:" + test.Error + ")$")
+		if rerr != nil {
+			return fmt.Sprintf("invalid error regexp %q: %v", test.Error, rerr)
+		}
+		if !re.MatchString(err.Error()) {
+			return fmt.Sprintf("error %q does not match %q", err.Error(), test.Error)
+		}
+	}
+	if test.Path != "" || test.Expected != "" {
+		var prefix string
+		if test.Path != "" {
+			prefix = test.Path + ": "
+		}
+		if test.Expected != "" {
+			prefix += "expected " + test.Expected + ", "
+		}
+		if !strings.HasPrefix(err.Error(), prefix) {
+			return fmt.Sprintf("error %q is not reported as %q", err.Error(), prefix+"...")
+		}
+	}
+	return ""
+}
+
+// Idempotent checks that every input is accepted by checker, and that
+// converting the coerced value back to plain data with schema.Uncoerce
+// and coercing it again is stable, as checked by schema.CheckCoerce.
+func Idempotent(t TestingT, checker schema.Checker, inputs ...interface{}) {
+	helper(t)
+	for i, input := range inputs {
+		if _, err := checker.Coerce(input, nil); err != nil {
+			t.Errorf("input %d: unexpected error coercing %#v: %v", i, input, err)
+			continue
+		}
+		if err := schema.CheckCoerce(checker, input); err != nil {
+			t.Errorf("input %d: %v", i, err)
+		}
+	}
+}
+
+// Defaults checks that input is accepted by checker, and that the
+// coerced map holds the values in want. Fields expected to be omitted
+// can be set to schema.Omit in want.
+func Defaults(t TestingT, checker schema.Checker, input interface{}, want map[string]interface{}) {
+	helper(t)
+	out, err := checker.Coerce(input, nil)
+	if err != nil {
+		t.Errorf("unexpected error coercing %#v: %v", input, err)
+		return
+	}
+	m, ok := out.(map[string]interface{})
+	if !ok {
+		t.Errorf("coercing %#v gives %#v, expected a map[string]interface{}", input, out)
+		return
+	}
+	for _, k := range sortedKeys(want) {
+		v, present := m[k]
+		switch {
+		case want[k] == schema.Omit:
+			if present {
+				t.Errorf("field %q is %#v, expected it to be omitted", k, v)
+			}
+		case !present:
+			t.Errorf("field %q is missing, expected %#v", k, want[k])
+		case !reflect.DeepEqual(v, want[k]):
+			t.Errorf("field %q is %T(%#v), expected %T(%#v)", k, v, v, want[k], want[k])
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func about(s string) string {
+	if s == "" {
+		return ""
+	}
+	return " (" + s + ")"
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schematest_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+	"github.com/juju/schema/schematest"
+)
+
+// The helpers can be used with gocheck.
+var _ schematest.TestingT = (*gc.C)(nil)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func assertErrors(t *testing.T, r *recorder, want ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(r.errors, want) {
+		t.Fatalf("got errors %#v, want %#v", r.errors, want)
+	}
+}
+
+var sch = schema.FieldMap(schema.Fields{
+	"name":  schema.String(),
+	"port":  schema.Int(),
+	"units": schema.List(schema.Int()),
+}, schema.Defaults{
+	"port":  80,
+	"units": schema.Omit,
+})
+
+func TestRun(t *testing.T) {
+	schematest.Run(t, sch, []schematest.Case{{
+		About:  "defaults",
+		Input:  map[string]interface{}{"name": "app"},
+		Output: map[string]interface{}{"name": "app", "port": int64(80)},
+	}, {
+		Input:    map[string]interface{}{"name": "app", "units": []interface{}{1, "x"}},
+		Path:     "units[1]",
+		Expected: "int",
+	}, {
+		Input: map[string]interface{}{"name": 1},
+		Error: `name: expected string, got int\(1\)`,
+	}, {
+		Input: map[string]interface{}{"name": "app", "port": "x"},
+		Path:  "port",
+	}})
+}
+
+func TestRunFailures(t *testing.T) {
+	r := &recorder{}
+	schematest.Run(r, sch, []schematest.Case{{
+		About:  "bad output",
+		Input:  map[string]interface{}{"name": "app"},
+		Output: map[string]interface{}{"name": "app"},
+	}, {
+		Input:    map[string]interface{}{"name": "app", "units": []interface{}{"x"}},
+		Path:     "units[1]",
+		Expected: "int",
+	}, {
+		Input: map[string]interface{}{"name": "app"},
+		Error: "name: .*",
+	}, {
+		Input:  map[string]interface{}{},
+		Output: nil,
+	}, {
+		Input: map[string]interface{}{"name": "app"},
+		Path:  "name",
+	}, {
+		Input: map[string]interface{}{"name": "app", "units": []interface{}{"x"}},
+		Path:  "units[1]",
+	}})
+	assertErrors(t, r,
+		`case 0 (bad output): coercing map[string]interface {}{"name":"app"} gives map[string]interface {}{"name":"app", "port":80}, expected map[string]interface {}{"name":"app"}`,
+		`case 1: error "units[0]: expected int, got string(\"x\")" is not reported as "units[1]: expected int, ..."`,
+		`case 2: coercing map[string]interface {}{"name":"app"} gives map[string]interface {}{"name":"app", "port":80}, expected an error`,
+		`case 3: unexpected error coercing map[string]interface {}{}: name: expected string, got nothing`,
+		`case 4: coercing map[string]interface {}{"name":"app"} gives map[string]interface {}{"name":"app", "port":80}, expected an error`,
+		`case 5: error "units[0]: expected int, got string(\"x\")" is not reported as "units[1]: ..."`,
+	)
+}
+
+func TestIdempotent(t *testing.T) {
+	schematest.Idempotent(t, schema.Size(), "1G", "1.5T")
+
+	r := &recorder{}
+	schematest.Idempotent(r, schema.Int(), "x")
+	assertErrors(t, r, `input 0: unexpected error coercing "x": expected int, got string("x")`)
+}
+
+func TestDefaults(t *testing.T) {
+	schematest.Defaults(t, sch, map[string]interface{}{"name": "app"}, map[string]interface{}{
+		"port":  int64(80),
+		"units": schema.Omit,
+	})
+
+	r := &recorder{}
+	schematest.Defaults(r, sch, map[string]interface{}{"name": "app", "units": []interface{}{}}, map[string]interface{}{
+		"name":  "other",
+		"port":  80,
+		"units": schema.Omit,
+	})
+	assertErrors(t, r,
+		`field "name" is string("app"), expected string("other")`,
+		`field "port" is int64(80), expected int(80)`,
+		`field "units" is []interface {}{}, expected it to be omitted`,
+	)
+}
@@ -6,6 +6,7 @@ package schema
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"strconv"
 	"strings"
 	"reflect"
@@ -45,6 +46,15 @@ func (c sizeC) Coerce(v interface{}, path []string) (interface{}, error) {
 	return v, nil
 }
 
+// Uncoerce implements Encoder.
+func (c sizeC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	mb, ok := v.(uint64)
+	if !ok {
+		return nil, error_{"uint64", v, path}
+	}
+	return formatSize(mb), nil
+}
+
 // parseSize parses the string as a size, in mebibytes.
 //
 // The string must be a is a non-negative number with
@@ -90,3 +100,29 @@ var sizeSuffixes = "MGTPEZY"
 func sizeSuffixMultiplier(i int) int {
 	return 1 << uint(i*10)
 }
+
+// formatSize returns a size in mebibytes formatted so that parseSize
+// returns it, using the largest multiplier suffix that represents it
+// exactly.
+func formatSize(mb uint64) string {
+	for i := len(sizeSuffixes) - 1; i > 0; i-- {
+		m := uint64(sizeSuffixMultiplier(i))
+		if mb != 0 && mb%m == 0 {
+			return fmt.Sprintf("%d%c", mb/m, sizeSuffixes[i])
+		}
+	}
+	return fmt.Sprintf("%dM", mb)
+}
+
+// Describe implements Descriptor.
+func (c sizeC) Describe() Description {
+	return Description{Kind: KindSize, Label: "size"}
+}
+
+func (c sizeC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "0", nil
+}
+
+func (c sizeC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return fmt.Sprintf("%d%c", 1+r.Intn(1024), "MGT"[r.Intn(3)]), nil
+}
@@ -72,3 +72,14 @@ func (*sizeSuite) TestParseSize(c *gc.C) {
 		}
 	}
 }
+
+func (*sizeSuite) TestFormatSize(c *gc.C) {
+	for _, mb := range []uint64{0, 1, 1023, 1024, 1536, 1 << 20, 3 << 30, 1 << 60, 1<<50 + 1} {
+		s := formatSize(mb)
+		c.Logf("%d: %s", mb, s)
+		parsed, err := parseSize(s)
+		c.Assert(err, gc.IsNil)
+		c.Assert(parsed, gc.Equals, mb)
+	}
+	c.Assert(formatSize(3<<30), gc.Equals, "3P")
+}
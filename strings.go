@@ -5,6 +5,7 @@ package schema
 
 import (
 	"fmt"
+	"math/rand"
 	"net/url"
 	"reflect"
 	"regexp"
@@ -153,3 +154,89 @@ func (c nonEmptyStringC) Coerce(v interface{}, path []string) (interface{}, erro
 	}
 	return nil, invalidError
 }
+
+// Uncoerce implements Encoder.
+func (c urlC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	if u, ok := v.(*url.URL); ok && u != nil {
+		return u.String(), nil
+	}
+	return nil, error_{"*url.URL", v, path}
+}
+
+// Describe implements Descriptor.
+func (c stringC) Describe() Description {
+	return Description{Kind: KindString, Label: "string"}
+}
+
+// Describe implements Descriptor.
+func (c urlC) Describe() Description {
+	return Description{Kind: KindURL, Label: "url"}
+}
+
+// Describe implements Descriptor.
+func (c sregexpC) Describe() Description {
+	return Description{Kind: KindRegexp, Label: "regexp"}
+}
+
+// Describe implements Descriptor.
+func (c uuidC) Describe() Description {
+	return Description{Kind: KindUUID, Label: "uuid"}
+}
+
+// Describe implements Descriptor.
+func (c stringifiedC) Describe() Description {
+	return Description{Kind: KindStringified, Label: "string", Options: describeAll(c.checkers)}
+}
+
+// Describe implements Descriptor.
+func (c nonEmptyStringC) Describe() Description {
+	return Description{Kind: KindNonEmptyString, Label: "non-empty " + c.valueLabel}
+}
+
+func (c stringC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "", nil
+}
+
+func (c urlC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "https://example.com", nil
+}
+
+func (c sregexpC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return ".*", nil
+}
+
+func (c uuidC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "00000000-0000-0000-0000-000000000000", nil
+}
+
+func (c stringifiedC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "", nil
+}
+
+func (c nonEmptyStringC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "example", nil
+}
+
+func (c stringC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return randomString(r, 0, 10), nil
+}
+
+func (c urlC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return fmt.Sprintf("https://%s.example.com/%s", randomWord(r), randomWord(r)), nil
+}
+
+func (c sregexpC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return regexp.QuoteMeta(randomString(r, 0, 10)), nil
+}
+
+func (c uuidC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", r.Uint32(), r.Intn(1<<16), r.Intn(1<<16), r.Intn(1<<16), r.Int63n(1<<48)), nil
+}
+
+func (c stringifiedC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return randomString(r, 0, 10), nil
+}
+
+func (c nonEmptyStringC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return randomString(r, 1, 10), nil
+}
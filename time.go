@@ -4,6 +4,7 @@
 package schema
 
 import (
+	"math/rand"
 	"reflect"
 	"time"
 )
@@ -39,3 +40,28 @@ func (c timeC) Coerce(v interface{}, path []string) (interface{}, error) {
 		return nil, error_{"string or time.Time", v, path}
 	}
 }
+
+// Uncoerce implements Encoder.
+func (c timeC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	t, ok := v.(time.Time)
+	if !ok {
+		return nil, error_{"time.Time", v, path}
+	}
+	if t.IsZero() {
+		return "", nil
+	}
+	return t.Format(time.RFC3339Nano), nil
+}
+
+// Describe implements Descriptor.
+func (c timeC) Describe() Description {
+	return Description{Kind: KindTime, Label: "time"}
+}
+
+func (c timeC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "1970-01-01T00:00:00Z", nil
+}
+
+func (c timeC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return time.Unix(r.Int63n(4e9), 0).UTC().Format(time.RFC3339), nil
+}
@@ -4,6 +4,7 @@
 package schema
 
 import (
+	"math/rand"
 	"reflect"
 	"time"
 )
@@ -40,3 +41,25 @@ func (c timeDurationC) Coerce(v interface{}, path []string) (interface{}, error)
 		return nil, error_{"string or time.Duration", v, path}
 	}
 }
+
+// Uncoerce implements Encoder.
+func (c timeDurationC) Uncoerce(v interface{}, path []string) (interface{}, error) {
+	d, ok := v.(time.Duration)
+	if !ok {
+		return nil, error_{"time.Duration", v, path}
+	}
+	return d.String(), nil
+}
+
+// Describe implements Descriptor.
+func (c timeDurationC) Describe() Description {
+	return Description{Kind: KindTimeDuration, Label: "duration"}
+}
+
+func (c timeDurationC) example(opts ExampleOptions, path []string) (interface{}, error) {
+	return "0s", nil
+}
+
+func (c timeDurationC) generate(r *rand.Rand, path []string) (interface{}, error) {
+	return (time.Duration(r.Int63n(1e6)) * time.Millisecond).String(), nil
+}
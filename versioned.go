@@ -0,0 +1,343 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration describes how to upgrade documents from one version to the
+// next, for the checker returned by Versioned.
+type Migration struct {
+	// From holds the version of the documents the migration applies
+	// to. They are upgraded to version From+1.
+	From int
+
+	// Steps holds the changes made to the documents, in order.
+	Steps []MigrationStep
+}
+
+// MigrationStep is a change made to a document by a Migration, such as
+// the ones returned by RenameKey, MoveKey, SetDefault and Transform.
+//
+// Steps address values with dot-separated key paths such as "db.host",
+// relative to the document root.
+type MigrationStep interface {
+	// migrate applies the step to doc, and returns the key path the
+	// step failed at along with the error.
+	migrate(doc map[string]interface{}) ([]string, error)
+}
+
+// Versioned returns a Checker that accepts a map holding a document
+// versioned by the value of the field named field. Documents older
+// than current are upgraded by applying the migrations from their
+// version up to current in turn, and the resulting document, with its
+// version field set to current, is processed with checker, which is
+// expected to accept it.
+//
+// Documents without a version field are assumed to be at the oldest
+// version migrations apply to. Processing fails if the version is not
+// an integer, or is newer than current or older than any migration,
+// and a failing step is reported with a *MigrationError.
+//
+// Versioned panics if the migrations don't upgrade documents one
+// version at a time up to current.
+func Versioned(field string, current int, checker Checker, migrations ...Migration) Checker {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].From < sorted[j].From
+	})
+	for i, m := range sorted {
+		if m.From+len(sorted)-i != current {
+			panic(fmt.Sprintf("Versioned got migrations that don't upgrade version %d to %d one version at a time", m.From, current))
+		}
+	}
+	oldest := current
+	if len(sorted) > 0 {
+		oldest = sorted[0].From
+	}
+	return versionedC{field, current, oldest, checker, sorted}
+}
+
+type versionedC struct {
+	field      string
+	current    int
+	oldest     int
+	checker    Checker
+	migrations []Migration
+}
+
+func (c versionedC) unwrap() Checker {
+	return c.checker
+}
+
+func (c versionedC) Coerce(v interface{}, path []string) (interface{}, error) {
+	m, ok := copyDocument(v).(map[string]interface{})
+	if !ok {
+		return nil, error_{"map with string keys", v, path}
+	}
+	version := c.oldest
+	if vv, ok := m[c.field]; ok {
+		vpath := append(path, ".", c.field)
+		n, err := ForceInt().Coerce(vv, vpath)
+		if err != nil {
+			return nil, err
+		}
+		if !integral(vv) {
+			return nil, error_{"integer", vv, vpath}
+		}
+		version = n.(int)
+	}
+	switch {
+	case version > c.current:
+		return nil, fmt.Errorf("%sversion %d is newer than the supported version %d", pathAsPrefix(path), version, c.current)
+	case version < c.oldest:
+		return nil, fmt.Errorf("%sversion %d is no longer supported, the oldest supported version is %d", pathAsPrefix(path), version, c.oldest)
+	}
+	for _, migration := range c.migrations[version-c.oldest:] {
+		for _, step := range migration.Steps {
+			kpath, err := step.migrate(m)
+			if err != nil {
+				return nil, &MigrationError{
+					Path:    append(append([]string{}, path...), keyPathElems(kpath)...),
+					Version: migration.From + 1,
+					Err:     err,
+				}
+			}
+		}
+	}
+	m[c.field] = c.current
+	return c.checker.Coerce(m, path)
+}
+
+// integral reports whether v, a value accepted by ForceInt, holds an
+// integer, so that versions such as 1.5 are not truncated.
+func integral(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == math.Trunc(rv.Float())
+	case reflect.String:
+		if _, err := strconv.ParseInt(rv.String(), 0, 64); err == nil {
+			return true
+		}
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		return err == nil && f == math.Trunc(f)
+	}
+	return true
+}
+
+// MigrationError is returned by the checker returned by Versioned when
+// a migration step fails.
+type MigrationError struct {
+	// Path holds the path to the value the step failed at.
+	Path []string
+
+	// Version holds the version the failing migration upgrades
+	// documents to.
+	Version int
+
+	// Err holds the error returned by the step.
+	Err error
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("%smigration to version %d: %v", pathAsPrefix(e.Path), e.Version, e.Err)
+}
+
+// RenameKey returns a MigrationStep that renames the key at path to
+// name, keeping it in the same map. Nothing is done if the key is not
+// present.
+func RenameKey(path, name string) MigrationStep {
+	keys := splitKeyPath(path)
+	return moveKeyStep{keys, append(keys[:len(keys)-1:len(keys)-1], name)}
+}
+
+// MoveKey returns a MigrationStep that moves the value at the key path
+// from to the key path to, creating the maps holding it as needed.
+// Nothing is done if the key is not present.
+func MoveKey(from, to string) MigrationStep {
+	return moveKeyStep{splitKeyPath(from), splitKeyPath(to)}
+}
+
+type moveKeyStep struct {
+	from []string
+	to   []string
+}
+
+func (s moveKeyStep) migrate(doc map[string]interface{}) ([]string, error) {
+	parent, err := lookupMap(doc, s.from[:len(s.from)-1], false)
+	if err != nil || parent == nil {
+		return s.from, err
+	}
+	key := s.from[len(s.from)-1]
+	v, ok := parent[key]
+	if !ok {
+		return nil, nil
+	}
+	target, err := lookupMap(doc, s.to[:len(s.to)-1], true)
+	if err != nil {
+		return s.to, err
+	}
+	if _, ok := target[s.to[len(s.to)-1]]; ok {
+		return s.to, fmt.Errorf("cannot move %s: key already present", strings.Join(s.from, "."))
+	}
+	delete(parent, key)
+	target[s.to[len(s.to)-1]] = v
+	return nil, nil
+}
+
+// SetDefault returns a MigrationStep that sets the key at path to
+// value if it is not present, creating the maps holding it as needed.
+func SetDefault(path string, value interface{}) MigrationStep {
+	return setDefaultStep{splitKeyPath(path), value}
+}
+
+type setDefaultStep struct {
+	keys  []string
+	value interface{}
+}
+
+func (s setDefaultStep) migrate(doc map[string]interface{}) ([]string, error) {
+	parent, err := lookupMap(doc, s.keys[:len(s.keys)-1], true)
+	if err != nil {
+		return s.keys, err
+	}
+	if _, ok := parent[s.keys[len(s.keys)-1]]; !ok {
+		parent[s.keys[len(s.keys)-1]] = s.value
+	}
+	return nil, nil
+}
+
+// Transform returns a MigrationStep that replaces the value at path
+// with the one returned by transform. Nothing is done if the key is not
+// present. If path is empty, transform is called with the whole
+// document, and must return a map with string keys.
+func Transform(path string, transform func(v interface{}) (interface{}, error)) MigrationStep {
+	var keys []string
+	if path != "" {
+		keys = splitKeyPath(path)
+	}
+	return transformStep{keys, transform}
+}
+
+type transformStep struct {
+	keys      []string
+	transform func(v interface{}) (interface{}, error)
+}
+
+func (s transformStep) migrate(doc map[string]interface{}) ([]string, error) {
+	if len(s.keys) == 0 {
+		v, err := s.transform(doc)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := copyDocument(v).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("transform returned %T instead of a map with string keys", v)
+		}
+		for k := range doc {
+			delete(doc, k)
+		}
+		for k, v := range m {
+			doc[k] = v
+		}
+		return nil, nil
+	}
+	parent, err := lookupMap(doc, s.keys[:len(s.keys)-1], false)
+	if err != nil || parent == nil {
+		return s.keys, err
+	}
+	key := s.keys[len(s.keys)-1]
+	v, ok := parent[key]
+	if !ok {
+		return nil, nil
+	}
+	newv, err := s.transform(v)
+	if err != nil {
+		return s.keys, err
+	}
+	parent[key] = copyDocument(newv)
+	return nil, nil
+}
+
+// lookupMap returns the map found at the key path in doc. If create is
+// true, missing maps are created; otherwise nil is returned for them.
+// An error is returned if a value on the path is not a map.
+func lookupMap(doc map[string]interface{}, keys []string, create bool) (map[string]interface{}, error) {
+	m := doc
+	for i, k := range keys {
+		v, ok := m[k]
+		if !ok {
+			if !create {
+				return nil, nil
+			}
+			child := make(map[string]interface{})
+			m[k] = child
+			m = child
+			continue
+		}
+		child, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is %T, not a map", strings.Join(keys[:i+1], "."), v)
+		}
+		m = child
+	}
+	return m, nil
+}
+
+// copyDocument returns a deep copy of v, with every map holding only
+// string keys converted to map[string]interface{}, so that migration
+// steps can change it without affecting the original value.
+func copyDocument(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if hasStrictStringKeys(rv) {
+			out := make(map[string]interface{}, rv.Len())
+			for _, k := range rv.MapKeys() {
+				out[keyString(k)] = copyDocument(rv.MapIndex(k).Interface())
+			}
+			return out
+		}
+		out := make(map[interface{}]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			out[k.Interface()] = copyDocument(rv.MapIndex(k).Interface())
+		}
+		return out
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = copyDocument(rv.Index(i).Interface())
+		}
+		return out
+	}
+	return v
+}
+
+func splitKeyPath(path string) []string {
+	if path == "" {
+		panic("empty key path")
+	}
+	return strings.Split(path, ".")
+}
+
+// keyPathElems returns the path elements used in error messages for
+// the given keys.
+func keyPathElems(keys []string) []string {
+	elems := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		elems = append(elems, ".", k)
+	}
+	return elems
+}
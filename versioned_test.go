@@ -0,0 +1,145 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package schema_test
+
+import (
+	"fmt"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/schema"
+)
+
+var versionedSchema = schema.Versioned("version", 3, schema.StrictFieldMap(schema.Fields{
+	"version": schema.Const(3),
+	"name":    schema.String(),
+	"db": schema.FieldMap(schema.Fields{
+		"host": schema.String(),
+		"port": schema.Int(),
+	}, nil),
+	"mode": schema.String(),
+}, nil),
+	schema.Migration{
+		From: 2,
+		Steps: []schema.MigrationStep{
+			schema.Transform("mode", func(v interface{}) (interface{}, error) {
+				s, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected string, got %T", v)
+				}
+				return strings.ToLower(s), nil
+			}),
+		},
+	},
+	schema.Migration{
+		From: 1,
+		Steps: []schema.MigrationStep{
+			schema.RenameKey("app-name", "name"),
+			schema.MoveKey("db-host", "db.host"),
+			schema.SetDefault("db.port", 5432),
+			schema.SetDefault("mode", "FAST"),
+		},
+	},
+)
+
+func (s *S) TestVersioned(c *gc.C) {
+	in := map[interface{}]interface{}{
+		"version":  1,
+		"app-name": "app",
+		"db-host":  "localhost",
+	}
+	out, err := versionedSchema.Coerce(in, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"version": 3,
+		"name":    "app",
+		"db":      map[string]interface{}{"host": "localhost", "port": int64(5432)},
+		"mode":    "fast",
+	})
+
+	// The input is left unchanged.
+	c.Assert(in, gc.DeepEquals, map[interface{}]interface{}{
+		"version":  1,
+		"app-name": "app",
+		"db-host":  "localhost",
+	})
+
+	// Documents without a version are at the oldest version.
+	out, err = versionedSchema.Coerce(map[string]interface{}{"app-name": "x", "db": map[string]interface{}{"host": "h"}}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out.(map[string]interface{})["db"], gc.DeepEquals, map[string]interface{}{"host": "h", "port": int64(5432)})
+
+	// Current documents are not migrated.
+	out, err = versionedSchema.Coerce(map[string]interface{}{
+		"version": "3",
+		"name":    "app",
+		"db":      map[string]interface{}{"host": "h", "port": 1},
+		"mode":    "SLOW",
+	}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out.(map[string]interface{})["mode"], gc.Equals, "SLOW")
+}
+
+func (s *S) TestVersionedErrors(c *gc.C) {
+	_, err := versionedSchema.Coerce(map[string]interface{}{"version": 4}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: version 4 is newer than the supported version 3`)
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": 0}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: version 0 is no longer supported, the oldest supported version is 1`)
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.version: expected number, got string\("x"\)`)
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": 1.5}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.version: expected integer, got float64\(1\.5\)`)
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": "2.5"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.version: expected integer, got string\("2\.5"\)`)
+
+	// Integral floats, as decoded from JSON, are accepted.
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": float64(4)}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: version 4 is newer than the supported version 3`)
+
+	_, err = versionedSchema.Coerce([]interface{}{}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>: expected map with string keys, got \[\]interface \{\}\(\[\]interface \{\}\{\}\)`)
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": 2, "mode": 1}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.mode: migration to version 3: expected string, got int`)
+	merr, ok := err.(*schema.MigrationError)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(merr.Version, gc.Equals, 3)
+	c.Assert(merr.Path, gc.DeepEquals, []string{"<pa", "th>", ".", "mode"})
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": 1, "db-host": "h", "db": "x"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.db\.host: migration to version 2: db is string, not a map`)
+
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": 1, "app-name": "a", "name": "b"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.name: migration to version 2: cannot move app-name: key already present`)
+
+	// Errors from the current schema are reported as usual.
+	_, err = versionedSchema.Coerce(map[string]interface{}{"version": 1, "app-name": 1, "db-host": "h"}, aPath)
+	c.Assert(err, gc.ErrorMatches, `<path>\.name: expected string, got int\(1\)`)
+}
+
+func (s *S) TestVersionedTransformDocument(c *gc.C) {
+	sch := schema.Versioned("v", 1, schema.StringMap(schema.Any()), schema.Migration{
+		From: 0,
+		Steps: []schema.MigrationStep{
+			schema.Transform("", func(v interface{}) (interface{}, error) {
+				m := v.(map[string]interface{})
+				return map[interface{}]interface{}{"count": len(m)}, nil
+			}),
+		},
+	})
+	out, err := sch.Coerce(map[string]interface{}{"a": 1, "b": 2}, aPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{"count": 2, "v": 1})
+}
+
+func (s *S) TestVersionedPanics(c *gc.C) {
+	c.Assert(func() {
+		schema.Versioned("v", 3, schema.Any(), schema.Migration{From: 1})
+	}, gc.PanicMatches, `Versioned got migrations that don't upgrade version 1 to 3 one version at a time`)
+}